@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/bot"
+	"github.com/ashokhin/am4bot/internal/breaker"
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/scheduler"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// account runs the bot of a single airline account with its own scheduler and circuit breaker.
+type account struct {
+	name string
+	bot  *bot.Bot
+	// failed runs are delayed with the exponential backoff,
+	// and the circuit breaker pauses automation while the site is down
+	breaker   *breaker.Breaker
+	scheduler *scheduler.Scheduler
+	// shutdownCtx is cancelled when the shutdown signal is received
+	shutdownCtx context.Context
+	// afterReload is called with the changed options after the reloaded configuration is applied
+	afterReload func(changes []config.Change)
+}
+
+// newAccount creates the bot of the account. All its metrics are registered with the "account" label.
+func newAccount(shutdownCtx context.Context, conf *config.Config, registry *prometheus.Registry) *account {
+	b := bot.New(conf, prometheus.WrapRegistererWith(prometheus.Labels{"account": conf.Name}, registry))
+
+	a := &account{
+		name:        conf.Name,
+		bot:         &b,
+		breaker:     breaker.New(resiliencePolicy(conf)),
+		shutdownCtx: shutdownCtx,
+	}
+
+	// create scheduler which runs all services due at the same tick within a single session
+	a.scheduler = scheduler.New(a.runJob)
+
+	a.bot.SetReloadHandler(a.applyReload)
+
+	return a
+}
+
+// applyReload applies the changed options which are used outside of the bot, e.g. schedules of services.
+func (a *account) applyReload(changes []config.Change) {
+	if slices.ContainsFunc(changes, isScheduleChange) {
+		if err := a.scheduler.Schedule(a.bot.Config().ServiceSchedules()); err != nil {
+			slog.Error("error in account.applyReload > reschedule services", "account", a.name, "error", err)
+		} else {
+			slog.Info("services have been rescheduled", "account", a.name, "next_run", a.scheduler.NextRun().UTC())
+		}
+	}
+
+	if a.afterReload != nil {
+		a.afterReload(changes)
+	}
+}
+
+// isScheduleChange reports whether the changed option affects the schedules of services.
+func isScheduleChange(change config.Change) bool {
+	return change.Option == "cron_schedule" || change.Option == "services" || strings.HasPrefix(change.Option, "schedules.")
+}
+
+// initialRun runs the bot once in the blocking mode (not inside the scheduler)
+// for collecting initial Prometheus metrics.
+func (a *account) initialRun(ctx context.Context) {
+	err := a.bot.Run(ctx)
+	if err != nil {
+		slog.Warn("error in Bot.Run", "account", a.name, "error", err)
+	}
+
+	a.recordRunResult(err)
+}
+
+// schedule creates cron jobs with schedules from configuration and starts the scheduler.
+func (a *account) schedule(ctx context.Context) error {
+	if err := a.scheduler.Schedule(a.bot.Config().ServiceSchedules()); err != nil {
+		return err
+	}
+
+	a.scheduler.Start(ctx)
+
+	slog.Info("job scheduled", "account", a.name, "next_run", a.scheduler.NextRun().UTC())
+
+	return nil
+}
+
+// runJob runs the services due at the same tick unless runs are delayed after failures.
+func (a *account) runJob(ctx context.Context, services []string) {
+	// thresholds could be changed by the reloaded configuration
+	a.breaker.SetPolicy(resiliencePolicy(a.bot.Config()))
+
+	if !a.allow(services) {
+		return
+	}
+
+	slog.Info("start job", "account", a.name, "start_time", time.Now().UTC(), "services", services,
+		"breaker_state", a.breaker.State())
+
+	err := a.bot.RunServices(ctx, services)
+
+	// the run stopped by the shutdown isn't a failure
+	if err != nil && a.shutdownCtx.Err() != nil {
+		slog.Info("job has been stopped by the shutdown", "account", a.name, "end_time", time.Now().UTC(), "error", err)
+
+		return
+	}
+
+	a.recordRunResult(err)
+
+	if err != nil {
+		slog.Error("job has been failed", "account", a.name, "end_time", time.Now().UTC(), "breaker_state", a.breaker.State(),
+			"failures", a.breaker.Failures(), "next_attempt", a.breaker.NextAttempt().UTC(), "error", err)
+	} else {
+		slog.Info("job has been done", "account", a.name, "end_time", time.Now().UTC(), "next_run", a.scheduler.NextRun().UTC())
+	}
+}
+
+// allow reports whether the run of the services is allowed by the breaker.
+// The open breaker is switched to half-open for the probe run, so the breaker metrics are updated.
+// The skipped run is recorded in the bot status, e.g. for the run requested with the API.
+func (a *account) allow(services []string) bool {
+	allowed := a.breaker.Allow(time.Now())
+
+	a.updateBreakerMetrics()
+
+	if !allowed {
+		slog.Warn("skip job after failed runs", "account", a.name, "breaker_state", a.breaker.State(),
+			"failures", a.breaker.Failures(), "next_attempt", a.breaker.NextAttempt().UTC(), "services", services)
+
+		a.bot.SkipRun(services, fmt.Sprintf("runs are delayed after %d failed runs, circuit breaker is %s",
+			a.breaker.Failures(), a.breaker.State()), a.breaker.NextAttempt().UTC())
+	}
+
+	return allowed
+}
+
+// recordRunResult updates the breaker and metrics with the result of the run.
+func (a *account) recordRunResult(err error) {
+	a.breaker.Record(time.Now(), err)
+
+	if err != nil {
+		a.bot.PrometheusMetrics.Up.Set(0)
+	} else {
+		a.bot.PrometheusMetrics.Up.Set(1)
+	}
+
+	a.updateBreakerMetrics()
+}
+
+// updateBreakerMetrics sets the metrics of the breaker state.
+func (a *account) updateBreakerMetrics() {
+	metrics := a.bot.PrometheusMetrics
+
+	metrics.BreakerState.Set(float64(a.breaker.State()))
+	metrics.ConsecutiveFailures.Set(float64(a.breaker.Failures()))
+
+	if nextAttempt := a.breaker.NextAttempt(); !nextAttempt.IsZero() {
+		metrics.NextAttemptTimestamp.Set(float64(nextAttempt.Unix()))
+	} else {
+		metrics.NextAttemptTimestamp.Set(0)
+	}
+}
+
+// resiliencePolicy returns the backoff and circuit breaker policy from the configuration.
+func resiliencePolicy(conf *config.Config) breaker.Policy {
+	return breaker.Policy{
+		InitialBackoff:   time.Duration(conf.Resilience.BackoffInitialSeconds) * time.Second,
+		MaxBackoff:       time.Duration(conf.Resilience.BackoffMaxSeconds) * time.Second,
+		FailureThreshold: conf.Resilience.FailureThreshold,
+		ProbeInterval:    time.Duration(conf.Resilience.ProbeIntervalSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/breaker"
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/promslog"
+)
+
+func TestRunJobDuringReload(t *testing.T) {
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	writeConfig := func(backoffSeconds int) {
+		// the unavailable remote browser fails the run without starting Chrome
+		content := fmt.Sprintf("chrome_remote_url: \"ws://127.0.0.1:1\"\nresilience:\n  backoff_initial_seconds: %d\n", backoffSeconds)
+
+		if err := os.WriteFile(confPath, []byte(content), 0600); err != nil {
+			t.Fatalf("os.WriteFile() returned error: %v", err)
+		}
+	}
+
+	writeConfig(0)
+
+	conf, err := config.New(confPath)
+	if err != nil {
+		t.Fatalf("config.New() returned error: %v", err)
+	}
+
+	conf.PromslogConfig = &promslog.Config{Level: promslog.NewLevel()}
+
+	a := newAccount(t.Context(), conf, prometheus.NewRegistry())
+	defer a.bot.Close()
+
+	reloaded := make(chan struct{})
+
+	// the config watcher reloads the configuration while the scheduler runs the jobs
+	go func() {
+		defer close(reloaded)
+
+		for i := range 20 {
+			writeConfig(i % 2)
+
+			if _, err := a.bot.ReloadConfig(); err != nil {
+				t.Errorf("ReloadConfig() returned error: %v", err)
+			}
+		}
+	}()
+
+	for range 20 {
+		a.runJob(t.Context(), []string{"depart"})
+	}
+
+	<-reloaded
+
+	if status := a.bot.Status(); status.LastRun == nil || status.LastRun.Success {
+		t.Errorf(`Status() returned '%+v', expected the failed run`, status)
+	}
+}
+
+func TestAllow(t *testing.T) {
+	conf, err := config.New(writeTestConfig(t, "resilience:\n  failure_threshold: 1\n  probe_interval_seconds: 3600\n"))
+	if err != nil {
+		t.Fatalf("config.New() returned error: %v", err)
+	}
+
+	a := newAccount(t.Context(), conf, prometheus.NewRegistry())
+	defer a.bot.Close()
+
+	runErr := errors.New("site is down")
+
+	// the probe interval is over, so the probe run is allowed
+	a.breaker.Record(time.Now().Add(-2*time.Hour), runErr)
+
+	if !a.allow([]string{"depart"}) {
+		t.Fatalf("allow() didn't allow the probe run")
+	}
+
+	if state := testutil.ToFloat64(a.bot.PrometheusMetrics.BreakerState); state != float64(breaker.HALF_OPEN) {
+		t.Errorf(`breaker state metric is '%v' during the probe run, expected '%v'`, state, float64(breaker.HALF_OPEN))
+	}
+
+	// the failed probe opens the breaker again, the next run is skipped
+	a.recordRunResult(runErr)
+
+	if a.allow([]string{"buy_fuel"}) {
+		t.Fatalf("allow() allowed the run while the breaker is open")
+	}
+
+	if skipped := a.bot.Status().LastSkipped; skipped == nil || !slices.Equal(skipped.Services, []string{"buy_fuel"}) || skipped.NextAttempt.IsZero() {
+		t.Errorf(`Status() returned the skipped run '%+v', expected the skip of '%v'`, skipped, []string{"buy_fuel"})
+	}
+}
+
+// writeTestConfig writes the configuration file with the content and returns its path.
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(confPath, []byte(content), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	return confPath
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ashokhin/am4bot/internal/config"
+)
+
+// checkConfig loads the configuration with all accounts and prints every invalid option.
+// It returns false if the configuration is invalid.
+func checkConfig(confPath string, overrides map[string]string) bool {
+	conf, err := config.NewWithOverrides(confPath, overrides)
+	if err == nil {
+		_, err = conf.Accounts()
+	}
+
+	if err == nil {
+		fmt.Printf("configuration %s is valid\n", confPath)
+
+		return true
+	}
+
+	fmt.Fprintf(os.Stderr, "configuration %s is invalid:\n", confPath)
+
+	var validationErr *config.ValidationError
+
+	if !errors.As(err, &validationErr) {
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+
+		return false
+	}
+
+	for _, fieldErr := range validationErr.Errors {
+		if validationErr.Account != "" {
+			fmt.Fprintf(os.Stderr, "  account %s: %v\n", validationErr.Account, fieldErr)
+		} else {
+			fmt.Fprintf(os.Stderr, "  %v\n", fieldErr)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/ledger"
+)
+
+// showLedger prints totals of the purchases recorded in the ledger
+// for the last days grouped by day and category for every account (or only the specified one).
+func showLedger(conf *config.Config, days int, accountName string) error {
+	accounts, err := conf.Accounts()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	// start from the local midnight of the first day in the period
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -(days - 1))
+	found := false
+
+	for _, account := range accounts {
+		if accountName != "" && account.Name != accountName {
+			continue
+		}
+
+		found = true
+
+		if len(accounts) > 1 {
+			fmt.Printf("\naccount: %s\n", account.Name)
+		}
+
+		if err := showAccountLedger(account, since); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("account %q is not found in the configuration", accountName)
+	}
+
+	return nil
+}
+
+// showAccountLedger prints totals of the account's purchases recorded since the specified time.
+func showAccountLedger(conf *config.Config, since time.Time) error {
+	if conf.LedgerFile == "" {
+		return fmt.Errorf("purchases ledger is disabled in the configuration (ledger_file)")
+	}
+
+	allEntries, err := ledger.New(conf.LedgerFile).Read(since)
+	if err != nil {
+		return err
+	}
+
+	// accounts could share the ledger file, entries recorded before the accounts
+	// were introduced have no account and belong to the default one
+	entries := slices.DeleteFunc(allEntries, func(e ledger.Entry) bool {
+		return e.Account != conf.Name && (e.Account != "" || conf.Name != config.DEFAULT_ACCOUNT_NAME)
+	})
+
+	totals := ledger.Summarize(entries, time.Local)
+	itemTotals := make(map[string]float64)
+	items := []string{}
+	grandTotal := 0.0
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(w, "DAY\tCATEGORY\tPURCHASES\tTOTAL\t")
+
+	for _, t := range totals {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.0f\t\n", t.Day, t.Item, t.Count, t.Total)
+
+		if _, ok := itemTotals[t.Item]; !ok {
+			items = append(items, t.Item)
+		}
+
+		itemTotals[t.Item] += t.Total
+		grandTotal += t.Total
+	}
+
+	fmt.Fprintln(w, "\t\t\t\t")
+
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t\t%.0f\t\n", "total", item, itemTotals[item])
+	}
+
+	fmt.Fprintf(w, "%s\t%s\t%d\t%.0f\t\n", "total", "all", len(entries), grandTotal)
+
+	return w.Flush()
+}
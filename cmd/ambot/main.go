@@ -4,13 +4,20 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/ashokhin/am4bot/internal/bot"
+	"github.com/ashokhin/am4bot/internal/api"
 	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/logbuffer"
+	"github.com/ashokhin/am4bot/internal/utils"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,20 +27,38 @@ import (
 	"github.com/prometheus/common/promslog"
 	"github.com/prometheus/common/promslog/flag"
 	"github.com/prometheus/common/version"
-	"github.com/robfig/cron/v3"
 )
 
 const (
-	APP_NAME             string = "ambot"
-	EXPORTER_NAME        string = "ambot_exporter"
-	EXPORTER_NAMESPACE   string = "am4"
-	MAX_RESTORE_ATTEMPTS int    = 5
+	APP_NAME           string = "ambot"
+	EXPORTER_NAME      string = "ambot_exporter"
+	EXPORTER_NAMESPACE string = "am4"
+	// HTTP_SHUTDOWN_TIMEOUT defines how long the HTTP server waits for active requests on shutdown
+	HTTP_SHUTDOWN_TIMEOUT time.Duration = 5 * time.Second
+	// LOG_LINES defines how many recent log lines are shown on the dashboard
+	LOG_LINES int = 200
 )
 
 var (
 	configFile   = kingpin.Flag("app.config", "YAML file with configuration.").Short('c').Default("config.yaml").String()
-	webAddr      = kingpin.Flag("web.listen-address", "Addresses on which to expose metrics and web interface.").Default(":9150").String()
+	webAddr      = kingpin.Flag("web.listen-address", "Addresses on which to expose metrics and web interface.").Default(":9150").IsSetByUser(&webAddrSet).String()
 	webTelemetry = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics.").Default("/metrics").String()
+	dryRun       = kingpin.Flag("app.dry-run", "Log purchase decisions without buying anything.").Bool()
+	configSet    = kingpin.Flag("config.set", "Override the option of the configuration file, e.g. --config.set=budget_percent.fuel=60. Can be repeated.").PlaceHolder("OPTION=VALUE").StringMap()
+
+	// explicitly set flags override the options of the configuration file
+	webAddrSet  bool
+	logLevelSet bool
+
+	runCommand    = kingpin.Command("run", "Run the bot and the Prometheus exporter.").Default()
+	ledgerCommand = kingpin.Command("ledger", "Show totals of the bot's purchases by day and category.")
+	ledgerDays    = ledgerCommand.Flag("days", "Number of the last days to show.").Default("7").Int()
+	ledgerAccount = ledgerCommand.Flag("account", "Name of the account to show, all accounts by default.").String()
+
+	selftestCommand = kingpin.Command("selftest", "Log in, open every pop-up and check that all selectors of the game UI resolve. Exits non-zero if any selector fails.")
+	selftestAccount = selftestCommand.Flag("account", "Name of the account to check, all accounts by default.").String()
+
+	checkConfigCommand = kingpin.Command("check-config", "Validate the configuration file with all accounts and print every invalid option. Exits non-zero if the configuration is invalid.")
 )
 
 func main() {
@@ -42,12 +67,15 @@ func main() {
 
 	promslogConfig := &promslog.Config{}
 	flag.AddFlags(kingpin.CommandLine, promslogConfig)
+	kingpin.CommandLine.GetFlag(flag.LevelFlagName).IsSetByUser(&logLevelSet)
 	kingpin.Version(version.Print(APP_NAME))
 	kingpin.HelpFlag.Short('h')
-	kingpin.Parse()
+	command := kingpin.Parse()
 
+	// keep recent log lines in memory for the dashboard
+	logs := logbuffer.New(LOG_LINES)
 	logger := promslog.New(promslogConfig)
-	slog.SetDefault(logger)
+	slog.SetDefault(slog.New(logs.Handler(logger.Handler())))
 
 	slog.Info(fmt.Sprintf("starting application %s", APP_NAME), "version", version.Info())
 	slog.Info("build context", "build_context", version.BuildContext())
@@ -55,7 +83,15 @@ func main() {
 	// load configuration
 	confPath, _ := filepath.Abs(*configFile)
 
-	if conf, err = config.New(confPath); err != nil {
+	if command == checkConfigCommand.FullCommand() {
+		if !checkConfig(confPath, configOverrides()) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if conf, err = config.NewWithOverrides(confPath, configOverrides()); err != nil {
 		slog.Error("config loading error", "error", err)
 
 		return
@@ -63,92 +99,156 @@ func main() {
 
 	conf.PromslogConfig = promslogConfig
 
-	// The CLI's "log.level" and config's "log_level" by default are both "info"
-	// if they are not -- check further
-	if conf.PromslogConfig.Level.String() != conf.LogLevel {
-		// If CLI's "log.level" is not default (info) then prioritize CLI's value
-		if conf.PromslogConfig.Level.String() != "info" {
-			slog.Info("set log level from CLI", "log.level", conf.PromslogConfig.Level.String())
+	if command == ledgerCommand.FullCommand() {
+		if err := showLedger(conf, *ledgerDays, *ledgerAccount); err != nil {
+			slog.Error("ledger error", "error", err)
 
-		} else { // else - set "log_level" from config
-			slog.Info("set log level from config", "log_level", conf.LogLevel)
-
-			conf.PromslogConfig.Level.Set(conf.LogLevel)
+			os.Exit(1)
 		}
+
+		return
+	}
+
+	// the CLI's "app.dry-run" enables the dry run mode even if it's disabled in the config
+	if *dryRun {
+		conf.ForceDryRun()
 	}
 
-	// The CLI's "web.listen-address" and config's "prometheus_address" by default are both ":9150"
-	// if they are not -- check further
-	if *webAddr != conf.PrometheusAddress {
-		// If CLI's "web.listen-address" is not default (:9150) then prioritize CLI's value
-		if *webAddr != ":9150" {
-			slog.Info("set Prometheus address from CLI", "address", *webAddr)
+	if conf.DryRun {
+		slog.Warn("dry run mode is enabled, nothing will be bought")
+	}
+
+	// the "log.level" flag is already applied as the "log_level" option
+	conf.PromslogConfig.Level.Set(conf.LogLevel)
+
+	slog.Info("effective configuration", "config", conf.EffectiveString())
+
+	if command == selftestCommand.FullCommand() {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		failed, err := runSelftest(ctx, conf, *selftestAccount)
+
+		stop()
+
+		if err != nil {
+			slog.Error("self-test error", "error", err)
+
+			os.Exit(1)
+		}
 
-		} else { // else - set "prometheus_address" from config
-			slog.Info("set Prometheus address from config", "address", conf.PrometheusAddress)
+		if failed > 0 {
+			slog.Error("self-test failed, selectors of the game UI have changed", "failed", failed)
 
-			*webAddr = conf.PrometheusAddress
+			os.Exit(1)
 		}
+
+		return
 	}
 
+	// the "web.listen-address" flag is already applied as the "prometheus_address" option
+	*webAddr = conf.PrometheusAddress
+
 	// create Prometheus registry
 	prometheusRegistry := prometheus.NewRegistry()
 	prometheusRegistry.MustRegister(versionCollector.NewCollector(APP_NAME))
 	prometheusRegistry.MustRegister(collectors.NewGoCollector())
 
-	// create Bot object with loaded configuration
-	bot := bot.New(conf, prometheusRegistry)
+	// the top-level configuration or every entry of the "accounts" list is the separate account
+	accountConfs, err := conf.Accounts()
+	if err != nil {
+		slog.Error("config loading error", "error", err)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+		return
+	}
 
-	// start it once in the blocking mode (not inside a goroutine)
-	// for collecting initial Prometheus metrics
-	if err := bot.Run(ctx); err != nil {
-		slog.Warn("error in Bot.Run", "error", err)
+	// shutdownCtx is cancelled when the shutdown signal is received
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		bot.PrometheusMetrics.Up.Set(0)
-	} else {
-		bot.PrometheusMetrics.Up.Set(1)
+	// HTTP server for Prometheus scraping, it's started after the initial runs
+	server := newHttpServer()
+
+	// create the bot of every account with its own browser profile and metrics
+	accounts := make([]*account, 0, len(accountConfs))
+
+	for _, accountConf := range accountConfs {
+		slog.Info("create account", "account", accountConf.Name, "user", utils.MaskUsername(accountConf.User))
+
+		accounts = append(accounts, newAccount(shutdownCtx, accountConf, prometheusRegistry))
 	}
 
-	// now start it inside "cronjob" (goroutine with schedule)
-	// create cron object
-	c := cron.New()
-	// add counter for restore attempts after error
-	restoreAttemptsCount := 0
-	// create cron job with schedule from configuration
-	c.AddFunc(bot.Conf.CronSchedule, func() {
-		slog.Info("start job", "start_time", time.Now().UTC())
+	// the HTTP server is shared by accounts, so its address is taken from the first account like on start
+	accounts[0].afterReload = func(changes []config.Change) {
+		if slices.ContainsFunc(changes, func(change config.Change) bool { return change.Option == "prometheus_address" }) {
+			server.restart(accounts[0].bot.Config().PrometheusAddress)
+		}
+	}
 
-		if err := bot.Run(ctx); err != nil {
-			// failed run increases counter
-			restoreAttemptsCount++
-			bot.PrometheusMetrics.Up.Set(0)
+	// runCtx is cancelled only if the in-flight runs aren't finished within the grace period
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
 
-			slog.Error("error in Bot.Run", "restore_attempts_count", restoreAttemptsCount, "max_attempts", MAX_RESTORE_ATTEMPTS, "error", err)
+	// botsStopped is closed when the bots don't run anymore
+	botsStopped := make(chan struct{})
 
-			if restoreAttemptsCount >= MAX_RESTORE_ATTEMPTS {
-				slog.Error("max restore attempts count has been reached. Exit.")
+	// on shutdown stop the bots at the safe point between services
+	// and cancel the in-flight runs (it closes the browsers) after the grace period
+	go func() {
+		<-shutdownCtx.Done()
 
-				os.Exit(1)
-			}
+		gracePeriod := shutdownGracePeriod(accounts)
 
-			slog.Error("job has been failed", "end_time", time.Now().UTC(), "next_run", c.Entry(1).Next.UTC())
-		} else {
-			// successful run resets counter
-			restoreAttemptsCount = 0
-			bot.PrometheusMetrics.Up.Set(1)
+		slog.Info("shutdown signal has been received, stopping the bots", "grace_period", fmt.Sprint(gracePeriod))
 
-			slog.Info("job has been done", "end_time", time.Now().UTC(), "next_run", c.Entry(1).Next.UTC())
+		for _, a := range accounts {
+			a.bot.Stop()
 		}
 
-	})
+		select {
+		case <-botsStopped:
+		case <-time.After(gracePeriod):
+			slog.Warn("shutdown grace period has been exceeded, cancel the current runs")
+
+			cancelRun()
+		}
+	}()
+
+	// run all accounts once in the blocking mode for collecting initial Prometheus metrics
+	var wg sync.WaitGroup
+
+	for _, a := range accounts {
+		wg.Go(func() {
+			a.initialRun(runCtx)
+		})
+	}
+
+	wg.Wait()
+
+	if shutdownCtx.Err() != nil {
+		close(botsStopped)
+		closeAccounts(accounts)
+		slog.Info("application has been stopped")
+
+		return
+	}
+
+	exitCode := 0
+
+	// now start them inside the schedulers (goroutines with per-service schedules)
+	scheduled := make([]*account, 0, len(accounts))
 
-	// start cron object, schedule jobs
-	c.Start()
+	for _, a := range accounts {
+		if err := a.schedule(runCtx); err != nil {
+			slog.Error("scheduling error", "account", a.name, "error", err)
 
-	slog.Info("job scheduled", "next_run", c.Entry(1).Next.UTC())
+			exitCode = 1
+			// stop the already scheduled accounts the same way as on the shutdown signal
+			stop()
+
+			break
+		}
+
+		scheduled = append(scheduled, a)
+	}
 
 	// create and register handler for the webTelemetry page
 	handler := promhttp.HandlerFor(
@@ -159,27 +259,105 @@ func main() {
 
 	http.Handle(*webTelemetry, handler)
 
-	// create and register handler for the root page
-	// for displaying version and redirecting to the webTelemetry page
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(`<html>
-			<head><title>AM4Bot Exporter</title></head>
-			<body>
-			<h1>AM4Bot Exporter</h1>
-			<p>` + version.Info() + `</p>
-			<p>` + version.BuildContext() + `</p>
-			<p>For Prometheus scraping use the metrics endpoint:</p>
-			<p><a href="` + *webTelemetry + `">` + *webTelemetry + `</a></p>
-			</body>
-			</html>`))
-	})
+	// register handlers of the control API and the dashboard on the root page
+	apiAccounts := make([]api.Account, 0, len(accounts))
+
+	for _, a := range accounts {
+		apiAccounts = append(apiAccounts, api.Account{Bot: a.bot, Scheduler: a.scheduler})
+	}
+
+	controlApi := api.New(logs, apiAccounts...)
+	controlApi.Register(http.DefaultServeMux)
+	controlApi.RegisterDashboard(http.DefaultServeMux, *webTelemetry)
 
 	slog.Info(fmt.Sprintf("starting Prometheus exporter %s", EXPORTER_NAME), "address", *webAddr, "location", *webTelemetry)
 
-	// start HTTP server for Prometheus scraping
-	if err := http.ListenAndServe(*webAddr, nil); err != nil {
+	// start HTTP server for Prometheus scraping, it isn't started if the bots are already stopping
+	if shutdownCtx.Err() == nil {
+		if err := server.start(*webAddr); err != nil {
+			slog.Error("error in http server", "error", err)
+
+			exitCode = 1
+			// stop the bot the same way as on the shutdown signal
+			stop()
+		}
+	}
+
+	// reload the configuration on changes of its files and on SIGHUP
+	go watchConfig(shutdownCtx, accounts)
+
+	select {
+	case <-shutdownCtx.Done():
+	case err := <-server.errors():
 		slog.Error("error in http server", "error", err)
 
-		os.Exit(1)
+		exitCode = 1
+		// stop the bot the same way as on the shutdown signal
+		stop()
+	}
+
+	// no new runs are started, wait for the in-flight runs of the started schedulers
+	for _, a := range scheduled {
+		a.scheduler.Stop()
+	}
+
+	for _, a := range scheduled {
+		<-a.scheduler.Done()
+	}
+
+	close(botsStopped)
+	closeAccounts(accounts)
+
+	slog.Info("bots have been stopped")
+
+	// let the HTTP server finish active requests
+	httpCtx, cancelHttp := context.WithTimeout(context.Background(), HTTP_SHUTDOWN_TIMEOUT)
+	defer cancelHttp()
+
+	if err := server.shutdown(httpCtx); err != nil {
+		slog.Warn("error in http server shutdown", "error", err)
+	}
+
+	slog.Info("application has been stopped")
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// configOverrides returns the options which override the configuration file:
+// the "config.set" flags and the explicitly set "web.listen-address" and "log.level" flags.
+func configOverrides() map[string]string {
+	overrides := make(map[string]string, len(*configSet)+2)
+
+	if webAddrSet {
+		overrides["prometheus_address"] = *webAddr
+	}
+
+	if logLevelSet {
+		overrides["log_level"] = kingpin.CommandLine.GetFlag(flag.LevelFlagName).Model().Value.String()
+	}
+
+	// the generic flag has priority over the dedicated ones
+	maps.Copy(overrides, *configSet)
+
+	return overrides
+}
+
+// shutdownGracePeriod returns the longest shutdown grace period of the accounts.
+func shutdownGracePeriod(accounts []*account) time.Duration {
+	var gracePeriod time.Duration
+
+	for _, a := range accounts {
+		gracePeriod = max(gracePeriod, time.Duration(a.bot.Config().ShutdownGraceSeconds)*time.Second)
+	}
+
+	return gracePeriod
+}
+
+// closeAccounts stops the persistent browsers of the accounts.
+func closeAccounts(accounts []*account) {
+	for _, a := range accounts {
+		a.bot.Close()
 	}
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// RELOAD_DEBOUNCE defines how long the watcher waits for the following changes of the files,
+// editors and Kubernetes write files in several steps.
+const RELOAD_DEBOUNCE time.Duration = 500 * time.Millisecond
+
+// watchConfig reloads the configuration of the accounts when the configuration file or the files
+// referred by it (secrets, selectors) are changed or the SIGHUP signal is received. It returns when the context is done.
+func watchConfig(ctx context.Context, accounts []*account) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	// events and errors of the watcher are nil channels if the watcher isn't available, so only SIGHUP works
+	var (
+		events <-chan fsnotify.Event
+		errs   <-chan error
+	)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("error in watchConfig > create file watcher, the configuration is reloaded only on SIGHUP", "error", err)
+	} else {
+		defer watcher.Close()
+
+		events, errs = watcher.Events, watcher.Errors
+	}
+
+	watched := watchFiles(watcher, accounts)
+
+	// the timer of the debounced reload, it's stopped until the first change
+	debounce := time.NewTimer(RELOAD_DEBOUNCE)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			slog.Info("SIGHUP has been received, reloading the configuration")
+
+			reloadAccounts(accounts)
+			watched = watchFiles(watcher, accounts)
+		case event := <-events:
+			// files of the mounted Kubernetes ConfigMap and Secret are replaced by the "..data" symlink
+			if !watched[filepath.Clean(event.Name)] && filepath.Base(event.Name) != "..data" {
+				continue
+			}
+
+			slog.Debug("configuration file has been changed", "file", event.Name, "operation", event.Op.String())
+
+			debounce.Reset(RELOAD_DEBOUNCE)
+		case err := <-errs:
+			slog.Warn("error in watchConfig > watch files", "error", err)
+		case <-debounce.C:
+			reloadAccounts(accounts)
+			watched = watchFiles(watcher, accounts)
+		}
+	}
+}
+
+// watchFiles adds the directories of the configuration files of the accounts to the watcher
+// and returns the set of the files. Directories are watched, so the replaced file is still watched.
+func watchFiles(watcher *fsnotify.Watcher, accounts []*account) map[string]bool {
+	watched := make(map[string]bool)
+
+	for _, a := range accounts {
+		for _, file := range a.bot.WatchedFiles() {
+			watched[file] = true
+		}
+	}
+
+	if watcher == nil {
+		return watched
+	}
+
+	for file := range watched {
+		// adding of the watched directory does nothing
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			slog.Warn("error in watchFiles > watch directory", "dir", filepath.Dir(file), "error", err)
+		}
+	}
+
+	return watched
+}
+
+// reloadAccounts reloads the configuration of every account, errors are logged by the bots.
+func reloadAccounts(accounts []*account) {
+	for _, a := range accounts {
+		a.bot.ReloadConfig()
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ashokhin/am4bot/internal/bot"
+	"github.com/ashokhin/am4bot/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runSelftest checks the selectors of the game UI with every account (or only the specified one)
+// and prints the report. It returns the number of failed selectors.
+func runSelftest(ctx context.Context, conf *config.Config, accountName string) (int, error) {
+	accounts, err := conf.Accounts()
+	if err != nil {
+		return 0, err
+	}
+
+	found := false
+	failed := 0
+
+	for _, account := range accounts {
+		if accountName != "" && account.Name != accountName {
+			continue
+		}
+
+		found = true
+
+		if len(accounts) > 1 {
+			fmt.Printf("\naccount: %s\n", account.Name)
+		}
+
+		accountFailed, err := runAccountSelftest(ctx, account)
+		if err != nil {
+			return failed, err
+		}
+
+		failed += accountFailed
+	}
+
+	if !found {
+		return 0, fmt.Errorf("account %q is not found in the configuration", accountName)
+	}
+
+	return failed, nil
+}
+
+// runAccountSelftest checks the selectors with the account and prints the report.
+func runAccountSelftest(ctx context.Context, conf *config.Config) (int, error) {
+	// metrics of the self-test aren't exposed
+	b := bot.New(conf, prometheus.NewRegistry())
+	defer b.Close()
+
+	results, err := b.Selftest(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	counts := make(map[bot.SelectorStatus]int)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "SELECTOR\tSTATUS\tSCREEN\tDETAIL")
+
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, r.Status, r.Screen, r.Detail)
+
+		counts[r.Status]++
+	}
+
+	fmt.Fprintln(w, "\t\t\t")
+	fmt.Fprintf(w, "total\t%d\tpassed: %d, failed: %d, skipped: %d\t\n", len(results),
+		counts[bot.SELECTOR_PASS], counts[bot.SELECTOR_FAIL], counts[bot.SELECTOR_SKIP])
+
+	return counts[bot.SELECTOR_FAIL], w.Flush()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// httpServer serves the metrics, the control API and the dashboard.
+// It's restarted on the new address when "prometheus_address" is changed.
+type httpServer struct {
+	mu     sync.Mutex
+	server *http.Server
+	errs   chan error
+}
+
+// newHttpServer creates the server of the default HTTP request multiplexer, it's started with start.
+func newHttpServer() *httpServer {
+	return &httpServer{errs: make(chan error, 1)}
+}
+
+// start listens on the address and serves requests in the background.
+// The server of the previous address is shut down after the new one is started.
+func (s *httpServer) start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Addr: addr}
+
+	s.mu.Lock()
+	previous := s.server
+	s.server = server
+	s.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			select {
+			case s.errs <- err:
+			default:
+			}
+		}
+	}()
+
+	if previous != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), HTTP_SHUTDOWN_TIMEOUT)
+		defer cancel()
+
+		if err := previous.Shutdown(ctx); err != nil {
+			slog.Warn("error in httpServer.start > shutdown previous server", "address", previous.Addr, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// restart starts the server on the changed address. The previous address is kept if the new one can't be used.
+// The server which isn't started yet is not restarted.
+func (s *httpServer) restart(addr string) {
+	s.mu.Lock()
+	current := s.server
+	s.mu.Unlock()
+
+	if current == nil || current.Addr == addr {
+		return
+	}
+
+	if err := s.start(addr); err != nil {
+		slog.Error("error in httpServer.restart, the previous address is kept", "address", current.Addr, "new_address", addr, "error", err)
+
+		return
+	}
+
+	slog.Info("HTTP server has been restarted on the new address", "address", addr, "previous_address", current.Addr)
+}
+
+// errors returns the channel of errors which stop the server.
+func (s *httpServer) errors() <-chan error {
+	return s.errs
+}
+
+// shutdown stops the server after active requests are finished.
+func (s *httpServer) shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	server := s.server
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	return server.Shutdown(ctx)
+}
@@ -95,13 +95,13 @@ func main() {
 
 	switch conf.ScanType {
 	case "route_scanner":
-		if err := scanRoutes(ctx, bot); err != nil {
+		if err := scanRoutes(ctx, &bot); err != nil {
 			slog.Error("error in main > scanRoutes", "error", err)
 
 			return
 		}
 	case "airport_scanner":
-		if err := scanAirports(ctx, bot); err != nil {
+		if err := scanAirports(ctx, &bot); err != nil {
 			slog.Error("error in main > scanAirports", "error", err)
 
 			return
@@ -117,7 +117,7 @@ func main() {
 	slog.Info("application finished")
 }
 
-func scanAirports(ctx context.Context, bot bot.Bot) error {
+func scanAirports(ctx context.Context, bot *bot.Bot) error {
 	if err := bot.ScanAirports(ctx); err != nil {
 		slog.Warn("error in main > bot.ScanAirports", "error", err)
 
@@ -127,7 +127,7 @@ func scanAirports(ctx context.Context, bot bot.Bot) error {
 	return nil
 }
 
-func scanRoutes(ctx context.Context, bot bot.Bot) error {
+func scanRoutes(ctx context.Context, bot *bot.Bot) error {
 	// calc values for progress bar
 	totalValues := calcValuesForProgress(bot.Conf)
 	slog.Debug("calc progress values", "totalPrCount", totalValues)
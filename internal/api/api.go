@@ -0,0 +1,362 @@
+package api
+
+import (
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/artifacts"
+	"github.com/ashokhin/am4bot/internal/bot"
+	"github.com/ashokhin/am4bot/internal/logbuffer"
+	"github.com/prometheus/common/version"
+)
+
+//go:embed web
+var webFS embed.FS
+
+// dashboardTemplate is the dashboard page which loads the bot state from the API.
+var dashboardTemplate = template.Must(template.ParseFS(webFS, "web/index.html"))
+
+// Scheduler is the part of the services scheduler controlled by the API.
+type Scheduler interface {
+	Enqueue(services ...string)
+	Pause()
+	Resume()
+	Paused() bool
+	NextRun() time.Time
+}
+
+// Account is the bot of the single airline account with its scheduler.
+type Account struct {
+	Bot       *bot.Bot
+	Scheduler Scheduler
+}
+
+// API serves the HTTP control endpoints of the bots.
+type API struct {
+	accounts []Account
+	logs     *logbuffer.Buffer
+}
+
+// runRequest is the optional body of the "POST /api/run" request.
+type runRequest struct {
+	Services []string `json:"services"`
+}
+
+// runResponse is the body of the "POST /api/run" response.
+type runResponse struct {
+	Services []string `json:"services"`
+}
+
+// pauseResponse is the body of the "POST /api/pause" and "POST /api/resume" responses.
+type pauseResponse struct {
+	Paused bool `json:"paused"`
+}
+
+// statusResponse is the body of the "GET /api/status" response.
+type statusResponse struct {
+	bot.Status
+	Account string     `json:"account"`
+	Paused  bool       `json:"paused"`
+	NextRun *time.Time `json:"next_run,omitempty"`
+}
+
+// accountsResponse is the body of the "GET /api/accounts" response.
+type accountsResponse struct {
+	Accounts []string `json:"accounts"`
+}
+
+// logsResponse is the body of the "GET /api/logs" response.
+type logsResponse struct {
+	Lines []string `json:"lines"`
+}
+
+// dashboardData holds the values rendered into the dashboard page.
+type dashboardData struct {
+	Version      string
+	BuildContext string
+	MetricsPath  string
+}
+
+// errorResponse is the body of the failed request response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// New creates a new API for the buffer of recent log lines and the accounts.
+// Requests without the "account" query parameter control the first account.
+func New(logs *logbuffer.Buffer, accounts ...Account) *API {
+	return &API{
+		accounts: accounts,
+		logs:     logs,
+	}
+}
+
+// Register adds the API endpoints to the HTTP request multiplexer.
+func (a *API) Register(mux *http.ServeMux) {
+	mux.Handle("POST /api/run", a.authorize(http.HandlerFunc(a.handleRun)))
+	mux.Handle("POST /api/pause", a.authorize(http.HandlerFunc(a.handlePause)))
+	mux.Handle("POST /api/resume", a.authorize(http.HandlerFunc(a.handleResume)))
+	mux.Handle("GET /api/status", a.authorize(http.HandlerFunc(a.handleStatus)))
+	mux.Handle("GET /api/accounts", a.authorize(http.HandlerFunc(a.handleAccounts)))
+	mux.Handle("GET /api/logs", a.authorize(http.HandlerFunc(a.handleLogs)))
+	mux.Handle("GET /api/config", a.authorize(http.HandlerFunc(a.handleConfig)))
+	mux.Handle("GET /api/artifacts/latest", a.authorize(http.HandlerFunc(a.handleLatestArtifact)))
+	mux.Handle("GET /api/artifacts/latest/{file}", a.authorize(http.HandlerFunc(a.handleLatestArtifactFile)))
+}
+
+// RegisterDashboard adds the dashboard page to the root of the HTTP request multiplexer.
+// The page itself doesn't contain any data, it's loaded from the API by the browser.
+func (a *API) RegisterDashboard(mux *http.ServeMux, metricsPath string) {
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		if err := dashboardTemplate.Execute(w, dashboardData{
+			Version:      version.Info(),
+			BuildContext: version.BuildContext(),
+			MetricsPath:  metricsPath,
+		}); err != nil {
+			slog.Warn("error in API.RegisterDashboard > render dashboard", "error", err)
+		}
+	})
+}
+
+// authorize checks the bearer token of the request if the "api_token" is set in the configuration.
+// The token of the first account is used, usually it's inherited from the top-level options.
+func (a *API) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := a.accounts[0].Bot.Config().ApiToken
+
+		if token != "" {
+			requestToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+			if !ok || subtle.ConstantTimeCompare([]byte(requestToken), []byte(token)) != 1 {
+				slog.Warn("unauthorized API request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// account returns the account from the "account" query parameter or the first account by default.
+// The error response is written if the account is unknown.
+func (a *API) account(w http.ResponseWriter, r *http.Request) (Account, bool) {
+	name := r.URL.Query().Get("account")
+	if name == "" {
+		return a.accounts[0], true
+	}
+
+	for _, account := range a.accounts {
+		if account.Bot.Config().Name == name {
+			return account, true
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("unknown account: %s", name)})
+
+	return Account{}, false
+}
+
+// handleRun enqueues the requested services (or all configured services) for the immediate run.
+func (a *API) handleRun(w http.ResponseWriter, r *http.Request) {
+	var request runRequest
+
+	account, ok := a.account(w, r)
+	if !ok {
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+
+		return
+	}
+
+	services := request.Services
+	if len(services) == 0 {
+		services = slices.Clone(account.Bot.Config().Services)
+	}
+
+	if unknown := bot.UnknownServices(services); len(unknown) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("unknown services: %s", strings.Join(unknown, ", "))})
+
+		return
+	}
+
+	slog.Info("run requested by API", "account", account.Bot.Config().Name, "services", services)
+
+	account.Scheduler.Enqueue(services...)
+
+	writeJSON(w, http.StatusAccepted, runResponse{Services: services})
+}
+
+// handlePause pauses the scheduled runs.
+func (a *API) handlePause(w http.ResponseWriter, r *http.Request) {
+	account, ok := a.account(w, r)
+	if !ok {
+		return
+	}
+
+	slog.Info("scheduler paused by API", "account", account.Bot.Config().Name)
+
+	account.Scheduler.Pause()
+
+	writeJSON(w, http.StatusOK, pauseResponse{Paused: true})
+}
+
+// handleResume resumes the scheduled runs.
+func (a *API) handleResume(w http.ResponseWriter, r *http.Request) {
+	account, ok := a.account(w, r)
+	if !ok {
+		return
+	}
+
+	slog.Info("scheduler resumed by API", "account", account.Bot.Config().Name)
+
+	account.Scheduler.Resume()
+
+	writeJSON(w, http.StatusOK, pauseResponse{Paused: false})
+}
+
+// handleStatus returns the bot status with the last run result and the scheduler state.
+func (a *API) handleStatus(w http.ResponseWriter, r *http.Request) {
+	account, ok := a.account(w, r)
+	if !ok {
+		return
+	}
+
+	status := statusResponse{
+		Status:  account.Bot.Status(),
+		Account: account.Bot.Config().Name,
+		Paused:  account.Scheduler.Paused(),
+	}
+
+	// scheduled runs are skipped while paused, so there is no next run
+	if nextRun := account.Scheduler.NextRun(); !status.Paused && !nextRun.IsZero() {
+		nextRun = nextRun.UTC()
+		status.NextRun = &nextRun
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleAccounts returns the names of all accounts, the first one is the default.
+func (a *API) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	response := accountsResponse{Accounts: make([]string, 0, len(a.accounts))}
+
+	for _, account := range a.accounts {
+		response.Accounts = append(response.Accounts, account.Bot.Config().Name)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleLogs returns the recent log lines from the oldest to the newest.
+func (a *API) handleLogs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, logsResponse{Lines: a.logs.Lines()})
+}
+
+// handleConfig returns the effective options of the account with the masked secrets.
+func (a *API) handleConfig(w http.ResponseWriter, r *http.Request) {
+	account, ok := a.account(w, r)
+	if !ok {
+		return
+	}
+
+	effective, err := account.Bot.Config().Effective()
+	if err != nil {
+		slog.Warn("error in API.handleConfig > effective config", "error", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, effective)
+}
+
+// latestArtifact returns the newest failure artifact of the account.
+// The error response is written if artifacts are disabled or there are no artifacts yet.
+func (a *API) latestArtifact(w http.ResponseWriter, r *http.Request) (*artifacts.Store, artifacts.Artifact, bool) {
+	account, ok := a.account(w, r)
+	if !ok {
+		return nil, artifacts.Artifact{}, false
+	}
+
+	store := account.Bot.Artifacts()
+	if store == nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "failure artifacts are disabled"})
+
+		return nil, artifacts.Artifact{}, false
+	}
+
+	artifact, err := store.Latest()
+	if err != nil {
+		if errors.Is(err, artifacts.ErrNotFound) {
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+		} else {
+			slog.Warn("error in API.latestArtifact > read artifact", "error", err)
+			writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		}
+
+		return nil, artifacts.Artifact{}, false
+	}
+
+	return store, artifact, true
+}
+
+// handleLatestArtifact returns the description of the newest failure artifact.
+func (a *API) handleLatestArtifact(w http.ResponseWriter, r *http.Request) {
+	if _, artifact, ok := a.latestArtifact(w, r); ok {
+		writeJSON(w, http.StatusOK, artifact)
+	}
+}
+
+// handleLatestArtifactFile returns the file (screenshot, pop-up HTML) of the newest failure artifact.
+func (a *API) handleLatestArtifactFile(w http.ResponseWriter, r *http.Request) {
+	store, artifact, ok := a.latestArtifact(w, r)
+	if !ok {
+		return
+	}
+
+	filePath, err := store.FilePath(artifact.Name, r.PathValue("file"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+
+		return
+	}
+
+	// the captured HTML of the game mustn't be executed in the context of the bot's server
+	w.Header().Set("Content-Security-Policy", "sandbox")
+
+	if filepath.Ext(filePath) == ".html" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	http.ServeFile(w, r, filePath)
+}
+
+// writeJSON writes the value as the JSON response with the status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("error in API.writeJSON", "error", err)
+	}
+}
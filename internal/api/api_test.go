@@ -0,0 +1,293 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/artifacts"
+	"github.com/ashokhin/am4bot/internal/bot"
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/logbuffer"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fakeScheduler records the calls of the API.
+type fakeScheduler struct {
+	enqueued []string
+	paused   bool
+	nextRun  time.Time
+}
+
+func (s *fakeScheduler) Enqueue(services ...string) { s.enqueued = append(s.enqueued, services...) }
+func (s *fakeScheduler) Pause()                     { s.paused = true }
+func (s *fakeScheduler) Resume()                    { s.paused = false }
+func (s *fakeScheduler) Paused() bool               { return s.paused }
+func (s *fakeScheduler) NextRun() time.Time         { return s.nextRun }
+
+func TestAPI(t *testing.T) {
+	testCases := map[string]struct {
+		method         string
+		path           string
+		body           string
+		token          string
+		expectedCode   int
+		expectedQueue  []string
+		expectedPaused bool
+	}{
+		"test01": {"POST", "/api/run", "", "secret", http.StatusAccepted, []string{"buy_fuel", "depart"}, false},
+		"test02": {"POST", "/api/run", `{"services":["depart"]}`, "secret", http.StatusAccepted, []string{"depart"}, false},
+		"test03": {"POST", "/api/run", `{"services":["fly_to_moon"]}`, "secret", http.StatusBadRequest, nil, false},
+		"test04": {"POST", "/api/run", `{"services":`, "secret", http.StatusBadRequest, nil, false},
+		"test05": {"POST", "/api/run", "", "", http.StatusUnauthorized, nil, false},
+		"test06": {"POST", "/api/run", "", "wrong", http.StatusUnauthorized, nil, false},
+		"test07": {"POST", "/api/pause", "", "secret", http.StatusOK, nil, true},
+		"test08": {"POST", "/api/resume", "", "secret", http.StatusOK, nil, false},
+		"test09": {"GET", "/api/status", "", "secret", http.StatusOK, nil, false},
+		"test10": {"GET", "/api/run", "", "secret", http.StatusMethodNotAllowed, nil, false},
+		"test11": {"GET", "/api/logs", "", "secret", http.StatusOK, nil, false},
+		"test12": {"GET", "/api/logs", "", "", http.StatusUnauthorized, nil, false},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			scheduler := &fakeScheduler{}
+			b := &bot.Bot{Conf: &config.Config{
+				Services: []string{"buy_fuel", "depart"},
+				ApiToken: "secret",
+			}}
+
+			mux := http.NewServeMux()
+			New(logbuffer.New(10), Account{b, scheduler}).Register(mux)
+
+			r := httptest.NewRequest(testData.method, testData.path, strings.NewReader(testData.body))
+			if testData.token != "" {
+				r.Header.Set("Authorization", "Bearer "+testData.token)
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if w.Code != testData.expectedCode {
+				t.Errorf(`%s %s returned code '%d', expected '%d'`, testData.method, testData.path, w.Code, testData.expectedCode)
+			}
+
+			if !slices.Equal(scheduler.enqueued, testData.expectedQueue) {
+				t.Errorf(`enqueued services '%+v', expected '%+v'`, scheduler.enqueued, testData.expectedQueue)
+			}
+
+			if scheduler.paused != testData.expectedPaused {
+				t.Errorf(`scheduler paused '%+v', expected '%+v'`, scheduler.paused, testData.expectedPaused)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	nextRun := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+	scheduler := &fakeScheduler{nextRun: nextRun}
+	b := &bot.Bot{Conf: &config.Config{}}
+
+	mux := http.NewServeMux()
+	New(logbuffer.New(10), Account{b, scheduler}).Register(mux)
+
+	getStatus := func() map[string]any {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/status", nil))
+
+		var status map[string]any
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatalf("json.Decode() returned error: %v", err)
+		}
+
+		return status
+	}
+
+	status := getStatus()
+
+	if status["next_run"] != "2026-10-17T10:05:00Z" {
+		t.Errorf(`status next_run '%v', expected '%v'`, status["next_run"], "2026-10-17T10:05:00Z")
+	}
+
+	if _, ok := status["budget"].(map[string]any)["fuel"]; !ok {
+		t.Errorf(`status '%v' doesn't contain the fuel budget`, status)
+	}
+
+	scheduler.Pause()
+	status = getStatus()
+
+	if _, ok := status["next_run"]; ok || status["paused"] != true {
+		t.Errorf(`status '%v' of the paused scheduler contains next_run`, status)
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	first := &fakeScheduler{}
+	second := &fakeScheduler{}
+
+	mux := http.NewServeMux()
+	New(logbuffer.New(10),
+		Account{&bot.Bot{Conf: &config.Config{Name: "first", Services: []string{"depart"}}}, first},
+		Account{&bot.Bot{Conf: &config.Config{Name: "second", Services: []string{"buy_fuel"}}}, second},
+	).Register(mux)
+
+	testCases := map[string]struct {
+		method         string
+		path           string
+		expectedCode   int
+		expectedFirst  []string
+		expectedSecond []string
+	}{
+		"test01": {"POST", "/api/run", http.StatusAccepted, []string{"depart"}, nil},
+		"test02": {"POST", "/api/run?account=second", http.StatusAccepted, nil, []string{"buy_fuel"}},
+		"test03": {"POST", "/api/run?account=third", http.StatusNotFound, nil, nil},
+		"test04": {"GET", "/api/status?account=third", http.StatusNotFound, nil, nil},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			first.enqueued, second.enqueued = nil, nil
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(testData.method, testData.path, nil))
+
+			if w.Code != testData.expectedCode {
+				t.Errorf(`%s %s returned code '%d', expected '%d'`, testData.method, testData.path, w.Code, testData.expectedCode)
+			}
+
+			if !slices.Equal(first.enqueued, testData.expectedFirst) || !slices.Equal(second.enqueued, testData.expectedSecond) {
+				t.Errorf(`enqueued services '%+v' and '%+v', expected '%+v' and '%+v'`,
+					first.enqueued, second.enqueued, testData.expectedFirst, testData.expectedSecond)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/accounts", nil))
+
+	var accounts accountsResponse
+	if err := json.NewDecoder(w.Body).Decode(&accounts); err != nil {
+		t.Fatalf("json.Decode() returned error: %v", err)
+	}
+
+	if !slices.Equal(accounts.Accounts, []string{"first", "second"}) {
+		t.Errorf(`accounts '%+v', expected '%+v'`, accounts.Accounts, []string{"first", "second"})
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	b := &bot.Bot{Conf: &config.Config{ApiToken: "secret"}}
+
+	mux := http.NewServeMux()
+	New(logbuffer.New(10), Account{b, &fakeScheduler{}}).RegisterDashboard(mux, "/metrics")
+
+	testCases := map[string]struct {
+		path         string
+		expectedCode int
+	}{
+		"test01": {"/", http.StatusOK},
+		"test02": {"/unknown", http.StatusNotFound},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", testData.path, nil))
+
+			if w.Code != testData.expectedCode {
+				t.Errorf(`GET %s returned code '%d', expected '%d'`, testData.path, w.Code, testData.expectedCode)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(w.Body.String(), `<a href="/metrics">`) {
+		t.Errorf("dashboard page doesn't contain the link to the metrics")
+	}
+}
+
+func TestLatestArtifact(t *testing.T) {
+	conf := &config.Config{Name: "first", ArtifactsDir: t.TempDir(), ArtifactsKeep: 5}
+	b := bot.New(conf, prometheus.NewRegistry())
+	defer b.Close()
+
+	mux := http.NewServeMux()
+	New(logbuffer.New(10),
+		Account{&b, &fakeScheduler{}},
+		Account{&bot.Bot{Conf: &config.Config{Name: "second"}}, &fakeScheduler{}},
+	).Register(mux)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		return w
+	}
+
+	if w := get("/api/artifacts/latest"); w.Code != http.StatusNotFound {
+		t.Errorf(`GET /api/artifacts/latest without artifacts returned code '%d', expected '%d'`, w.Code, http.StatusNotFound)
+	}
+
+	if _, err := b.Artifacts().Save(artifacts.Artifact{Time: time.Now(), Service: "depart", Error: "no planes"}, []byte("png"), "<div></div>"); err != nil {
+		t.Fatalf("Store.Save() returned error: %v", err)
+	}
+
+	testCases := map[string]struct {
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		"test01": {"/api/artifacts/latest", http.StatusOK, `"error":"no planes"`},
+		"test02": {"/api/artifacts/latest/screenshot.png", http.StatusOK, "png"},
+		"test03": {"/api/artifacts/latest/popup.html", http.StatusOK, "<div></div>"},
+		"test04": {"/api/artifacts/latest/config.yaml", http.StatusNotFound, "artifact not found"},
+		"test05": {"/api/artifacts/latest?account=second", http.StatusNotFound, "disabled"},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			w := get(testData.path)
+
+			if w.Code != testData.expectedCode {
+				t.Errorf(`GET %s returned code '%d', expected '%d'`, testData.path, w.Code, testData.expectedCode)
+			}
+
+			if !strings.Contains(w.Body.String(), testData.expectedBody) {
+				t.Errorf(`GET %s returned body '%s', expected to contain '%s'`, testData.path, w.Body.String(), testData.expectedBody)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	b := &bot.Bot{Conf: &config.Config{Name: "first", User: "username@example.com", ApiToken: "secret", Services: []string{"depart"}}}
+
+	mux := http.NewServeMux()
+	New(logbuffer.New(10), Account{b, &fakeScheduler{}}).Register(mux)
+
+	r := httptest.NewRequest("GET", "/api/config", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	var effective map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&effective); err != nil {
+		t.Fatalf("json.Decode() returned error: %v", err)
+	}
+
+	if effective["api_token"] != config.MASKED_VALUE || effective["username"] == "username@example.com" {
+		t.Errorf(`effective config '%v' contains secrets`, effective)
+	}
+
+	if effective["name"] != "first" || !slices.Equal(effective["services"].([]any), []any{"depart"}) {
+		t.Errorf(`effective config '%v' doesn't contain options`, effective)
+	}
+}
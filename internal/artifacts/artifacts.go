@@ -0,0 +1,209 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TIME_LAYOUT defines the timestamp prefix of the artifact directory, so directories are sorted by time.
+	TIME_LAYOUT string = "20060102T150405.000Z"
+	// INFO_FILE, SCREENSHOT_FILE and HTML_FILE are the files of the artifact.
+	INFO_FILE       string = "info.json"
+	SCREENSHOT_FILE string = "screenshot.png"
+	HTML_FILE       string = "popup.html"
+)
+
+// ErrNotFound is returned when there are no artifacts or the requested file doesn't exist.
+var ErrNotFound = errors.New("artifact not found")
+
+// Artifact describes the page state captured when a service fails.
+type Artifact struct {
+	Name    string    `json:"name"`
+	Time    time.Time `json:"time"`
+	Account string    `json:"account,omitempty"`
+	Service string    `json:"service"`
+	Error   string    `json:"error"`
+	URL     string    `json:"url"`
+	// Console holds the browser console errors and failed network requests of the run.
+	Console []string `json:"console"`
+	// Files lists the files saved in the artifact directory.
+	Files []string `json:"files"`
+}
+
+// Store keeps the failure artifacts in timestamped subdirectories of the directory.
+// Only the newest artifacts are kept.
+type Store struct {
+	dir  string
+	keep int
+	mu   sync.Mutex
+}
+
+// New creates a new Store for the directory which keeps the specified number of the newest artifacts.
+// All artifacts are kept if keep is zero.
+func New(dir string, keep int) *Store {
+	return &Store{
+		dir:  dir,
+		keep: keep,
+	}
+}
+
+// Dir returns the directory of the artifacts.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes the artifact with the screenshot and the HTML of the page into the new directory
+// and removes the oldest artifacts over the retention limit. Empty files are not written.
+func (s *Store) Save(a Artifact, screenshot []byte, html string) (Artifact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a.Name = a.Time.UTC().Format(TIME_LAYOUT) + "_" + sanitize(a.Service)
+	artifactDir := filepath.Join(s.dir, a.Name)
+
+	if err := os.MkdirAll(artifactDir, 0750); err != nil {
+		return a, err
+	}
+
+	files := map[string][]byte{
+		SCREENSHOT_FILE: screenshot,
+		HTML_FILE:       []byte(html),
+	}
+
+	for _, name := range []string{SCREENSHOT_FILE, HTML_FILE} {
+		if len(files[name]) == 0 {
+			continue
+		}
+
+		if err := os.WriteFile(filepath.Join(artifactDir, name), files[name], 0640); err != nil {
+			return a, err
+		}
+
+		a.Files = append(a.Files, name)
+	}
+
+	a.Files = append(a.Files, INFO_FILE)
+
+	info, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return a, err
+	}
+
+	if err := os.WriteFile(filepath.Join(artifactDir, INFO_FILE), info, 0640); err != nil {
+		return a, err
+	}
+
+	s.prune()
+
+	return a, nil
+}
+
+// Latest returns the newest artifact.
+func (s *Store) Latest() (Artifact, error) {
+	var a Artifact
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names, err := s.names()
+	if err != nil {
+		return a, err
+	}
+
+	if len(names) == 0 {
+		return a, ErrNotFound
+	}
+
+	info, err := os.ReadFile(filepath.Join(s.dir, names[len(names)-1], INFO_FILE))
+	if err != nil {
+		return a, err
+	}
+
+	err = json.Unmarshal(info, &a)
+
+	return a, err
+}
+
+// FilePath returns the path of the file of the named artifact.
+func (s *Store) FilePath(name, file string) (string, error) {
+	if name != filepath.Base(name) || !slices.Contains([]string{INFO_FILE, SCREENSHOT_FILE, HTML_FILE}, file) {
+		return "", ErrNotFound
+	}
+
+	filePath := filepath.Join(s.dir, name, file)
+
+	if _, err := os.Stat(filePath); err != nil {
+		return "", ErrNotFound
+	}
+
+	return filePath, nil
+}
+
+// names returns names of the artifact directories from the oldest to the newest.
+func (s *Store) names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var names []string
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		if _, err := time.Parse(TIME_LAYOUT, strings.SplitN(e.Name(), "_", 2)[0]); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// prune removes the oldest artifacts over the retention limit, the caller must hold the lock.
+func (s *Store) prune() {
+	if s.keep <= 0 {
+		return
+	}
+
+	names, err := s.names()
+	if err != nil {
+		slog.Warn("error in Store.prune > read artifacts", "dir", s.dir, "error", err)
+
+		return
+	}
+
+	for len(names) > s.keep {
+		if err := os.RemoveAll(filepath.Join(s.dir, names[0])); err != nil {
+			slog.Warn("error in Store.prune > remove artifact", "name", names[0], "error", err)
+		}
+
+		names = names[1:]
+	}
+}
+
+// sanitize replaces characters which are not allowed in the directory name.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+
+		return '-'
+	}, name)
+}
@@ -0,0 +1,130 @@
+package artifacts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSave(t *testing.T) {
+	testCases := map[string]struct {
+		screenshot    []byte
+		html          string
+		expectedFiles []string
+	}{
+		"test01": {[]byte("png"), "<div></div>", []string{SCREENSHOT_FILE, HTML_FILE, INFO_FILE}},
+		"test02": {nil, "<div></div>", []string{HTML_FILE, INFO_FILE}},
+		"test03": {nil, "", []string{INFO_FILE}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			s := New(t.TempDir(), 0)
+
+			a, err := s.Save(Artifact{
+				Time:    time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC),
+				Service: "buy fuel",
+				Error:   "fuel price not found",
+			}, testData.screenshot, testData.html)
+			if err != nil {
+				t.Fatalf("Store.Save() returned error: %v", err)
+			}
+
+			if a.Name != "20261017T100500.000Z_buy-fuel" {
+				t.Errorf(`artifact name '%s', expected '%s'`, a.Name, "20261017T100500.000Z_buy-fuel")
+			}
+
+			if !slices.Equal(a.Files, testData.expectedFiles) {
+				t.Errorf(`artifact files '%+v', expected '%+v'`, a.Files, testData.expectedFiles)
+			}
+
+			for _, file := range testData.expectedFiles {
+				if _, err := s.FilePath(a.Name, file); err != nil {
+					t.Errorf("Store.FilePath(%s) returned error: %v", file, err)
+				}
+			}
+
+			latest, err := s.Latest()
+			if err != nil {
+				t.Fatalf("Store.Latest() returned error: %v", err)
+			}
+
+			if latest.Name != a.Name || latest.Error != a.Error {
+				t.Errorf(`latest artifact '%+v', expected '%+v'`, latest, a)
+			}
+		})
+	}
+}
+
+func TestRetention(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir, 2)
+
+	// unrelated directories aren't artifacts
+	if err := os.Mkdir(filepath.Join(dir, "other"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Latest(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Store.Latest() of the empty store returned error '%v', expected '%v'", err, ErrNotFound)
+	}
+
+	start := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	for i, service := range []string{"depart", "buy_fuel", "buy_co2"} {
+		if _, err := s.Save(Artifact{Time: start.Add(time.Duration(i) * time.Minute), Service: service}, nil, ""); err != nil {
+			t.Fatalf("Store.Save() returned error: %v", err)
+		}
+	}
+
+	names, err := s.names()
+	if err != nil {
+		t.Fatalf("Store.names() returned error: %v", err)
+	}
+
+	expectedNames := []string{"20261017T100600.000Z_buy_fuel", "20261017T100700.000Z_buy_co2"}
+
+	if !slices.Equal(names, expectedNames) {
+		t.Errorf(`artifacts '%+v', expected '%+v'`, names, expectedNames)
+	}
+
+	if latest, _ := s.Latest(); latest.Service != "buy_co2" {
+		t.Errorf(`latest artifact service '%s', expected '%s'`, latest.Service, "buy_co2")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "other")); err != nil {
+		t.Errorf("unrelated directory is removed: %v", err)
+	}
+}
+
+func TestFilePath(t *testing.T) {
+	s := New(t.TempDir(), 0)
+
+	a, err := s.Save(Artifact{Time: time.Now(), Service: "depart"}, nil, "<div></div>")
+	if err != nil {
+		t.Fatalf("Store.Save() returned error: %v", err)
+	}
+
+	testCases := map[string]struct {
+		name     string
+		file     string
+		expected error
+	}{
+		"test01": {a.Name, HTML_FILE, nil},
+		"test02": {a.Name, SCREENSHOT_FILE, ErrNotFound},
+		"test03": {a.Name, "../" + INFO_FILE, ErrNotFound},
+		"test04": {"../" + a.Name, INFO_FILE, ErrNotFound},
+		"test05": {a.Name, "secret.txt", ErrNotFound},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			if _, err := s.FilePath(testData.name, testData.file); !errors.Is(err, testData.expected) {
+				t.Errorf("Store.FilePath(%s, %s) returned error '%v', expected '%v'", testData.name, testData.file, err, testData.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/artifacts"
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/model"
+
+	"github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// CONSOLE_LOG_LINES defines how many browser console errors of the run are kept for the failure artifact.
+	CONSOLE_LOG_LINES int = 100
+	// ARTIFACT_TIMEOUT defines how long the bot waits for the page capture, it isn't limited by the run timeout.
+	ARTIFACT_TIMEOUT time.Duration = 30 * time.Second
+)
+
+// consoleLog collects browser console errors and failed network requests of the run.
+type consoleLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// listenConsole starts collecting console errors of the browser tab until the context is done.
+func listenConsole(ctx context.Context) *consoleLog {
+	c := &consoleLog{}
+
+	chromedp.ListenTarget(ctx, func(ev any) {
+		switch e := ev.(type) {
+		case *runtime.EventConsoleAPICalled:
+			if e.Type != runtime.APITypeError && e.Type != runtime.APITypeWarning && e.Type != runtime.APITypeAssert {
+				return
+			}
+
+			args := make([]string, 0, len(e.Args))
+
+			for _, arg := range e.Args {
+				if arg.Value != nil {
+					args = append(args, string(arg.Value))
+				} else {
+					args = append(args, arg.Description)
+				}
+			}
+
+			c.add("console %s: %s", e.Type, strings.Join(args, " "))
+		case *runtime.EventExceptionThrown:
+			description := e.ExceptionDetails.Text
+
+			if e.ExceptionDetails.Exception != nil && e.ExceptionDetails.Exception.Description != "" {
+				description = e.ExceptionDetails.Exception.Description
+			}
+
+			c.add("exception: %s (%s:%d)", description, e.ExceptionDetails.URL, e.ExceptionDetails.LineNumber)
+		case *log.EventEntryAdded:
+			if e.Entry.Level == log.LevelError {
+				c.add("%s error: %s %s", e.Entry.Source, e.Entry.Text, e.Entry.URL)
+			}
+		case *network.EventResponseReceived:
+			if e.Response.Status >= 400 {
+				c.add("network: %s %d %s", e.Response.URL, e.Response.Status, e.Response.StatusText)
+			}
+		case *network.EventLoadingFailed:
+			if !e.Canceled {
+				c.add("network: %s request failed: %s", e.Type, e.ErrorText)
+			}
+		}
+	})
+
+	return c
+}
+
+// add appends the timestamped line, the oldest lines over the limit are dropped.
+func (c *consoleLog) add(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lines = append(c.lines, time.Now().UTC().Format(time.RFC3339)+" "+fmt.Sprintf(format, args...))
+
+	if len(c.lines) > CONSOLE_LOG_LINES {
+		c.lines = c.lines[len(c.lines)-CONSOLE_LOG_LINES:]
+	}
+}
+
+// Lines returns the collected lines from the oldest to the newest.
+func (c *consoleLog) Lines() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string{}, c.lines...)
+}
+
+// newArtifactStore creates the store of the failure artifacts of the account if it's enabled in the configuration.
+func newArtifactStore(conf *config.Config) *artifacts.Store {
+	if conf.ArtifactsDir == "" {
+		slog.Debug("failure artifacts are disabled")
+
+		return nil
+	}
+
+	return artifacts.New(filepath.Join(conf.ArtifactsDir, conf.Name), conf.ArtifactsKeep)
+}
+
+// Artifacts returns the store of the failure artifacts or nil if they are disabled.
+func (b *Bot) Artifacts() *artifacts.Store {
+	return b.artifactStore
+}
+
+// saveFailureArtifact captures the screenshot, the opened pop-up, the URL and the console errors
+// after the failure of the service. Errors of the capture are only logged.
+func (b *Bot) saveFailureArtifact(ctx context.Context, serviceName string, serviceErr error, console *consoleLog) {
+	store := b.Artifacts()
+	if store == nil {
+		return
+	}
+
+	// the run could fail by its timeout, so the capture isn't limited by the run context
+	captureCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ARTIFACT_TIMEOUT)
+	defer cancel()
+
+	capture, err := b.Page.Capture(captureCtx, model.ELEMENT_COMMON_POPUP)
+	if err != nil {
+		slog.Warn("error in Bot.saveFailureArtifact > capture page", "service", serviceName, "error", err)
+	}
+
+	a := artifacts.Artifact{
+		Time:    time.Now(),
+		Account: b.Conf.Name,
+		Service: serviceName,
+		Error:   serviceErr.Error(),
+		URL:     capture.URL,
+	}
+
+	if console != nil {
+		a.Console = console.Lines()
+	}
+
+	a, err = store.Save(a, capture.Screenshot, capture.HTML)
+	if err != nil {
+		slog.Warn("error in Bot.saveFailureArtifact > save artifact", "service", serviceName, "error", err)
+
+		return
+	}
+
+	slog.Info("failure artifact has been saved", "service", serviceName, "dir", filepath.Join(store.Dir(), a.Name))
+}
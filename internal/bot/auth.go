@@ -6,12 +6,11 @@ import (
 
 	"github.com/ashokhin/am4bot/internal/model"
 	"github.com/ashokhin/am4bot/internal/utils"
-	"github.com/chromedp/chromedp"
 )
 
 // auth performs authentication on the target website using credentials from the bot configuration.
 func (b *Bot) auth(ctx context.Context) error {
-	if !utils.IsElementVisible(ctx, model.BUTTON_PLAY_NOW) {
+	if !b.Page.Visible(ctx, model.BUTTON_PLAY_NOW, VISIBILITY_TIMEOUT) {
 		slog.Debug("already authenticated, skipping auth step")
 
 		return nil
@@ -20,21 +19,21 @@ func (b *Bot) auth(ctx context.Context) error {
 	slog.Info("performing authentication")
 	slog.Debug("auth", "url", b.Conf.Url, "user", utils.MaskUsername(b.Conf.User))
 
-	if err := chromedp.Run(ctx,
+	if err := runSteps(
 		// open login page
-		chromedp.Navigate(b.Conf.Url),
+		func() error { return b.Page.Navigate(ctx, b.Conf.Url) },
 		// perform login steps
-		chromedp.Click(model.BUTTON_PLAY_NOW, chromedp.ByQuery),
-		chromedp.Click(model.BUTTON_LOGIN, chromedp.ByQuery),
-		chromedp.WaitReady(model.TEXT_FIELD_LOGIN, chromedp.ByQuery),
+		func() error { return b.Page.Click(ctx, model.BUTTON_PLAY_NOW) },
+		func() error { return b.Page.Click(ctx, model.BUTTON_LOGIN) },
+		func() error { return b.Page.WaitReady(ctx, model.TEXT_FIELD_LOGIN) },
 		// fill in credentials and submit
-		chromedp.SendKeys(model.TEXT_FIELD_LOGIN, b.Conf.User, chromedp.ByQuery),
-		chromedp.SendKeys(model.TEXT_FIELD_PASSWORD, b.Conf.GetPassword(), chromedp.ByQuery),
-		chromedp.SetAttributeValue(model.CHECKBOX_REMEMBER, "checked", "checked", chromedp.ByQuery),
-		chromedp.Click(model.BUTTON_AUTH, chromedp.ByQuery),
+		func() error { return b.Page.SendKeys(ctx, model.TEXT_FIELD_LOGIN, b.Conf.User) },
+		func() error { return b.Page.SendKeys(ctx, model.TEXT_FIELD_PASSWORD, b.Conf.GetPassword()) },
+		func() error { return b.Page.SetAttribute(ctx, model.CHECKBOX_REMEMBER, "checked", "checked") },
+		func() error { return b.Page.Click(ctx, model.BUTTON_AUTH) },
 		// wait for main page to load
-		chromedp.WaitNotVisible(model.OVERLAY_LOADING, chromedp.ByQuery),
-		utils.RefreshPage(),
+		func() error { return b.Page.WaitNotVisible(ctx, model.OVERLAY_LOADING) },
+		func() error { return b.Page.Reload(ctx) },
 	); err != nil {
 		slog.Warn("error in bot.auth", "error", err)
 
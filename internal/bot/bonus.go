@@ -3,13 +3,15 @@ package bot
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/ashokhin/am4bot/internal/model"
-	"github.com/ashokhin/am4bot/internal/utils"
-
-	"github.com/chromedp/chromedp"
 )
 
+func init() {
+	RegisterService(service{name: "claim_rewards", run: (*Bot).claimRewards})
+}
+
 // claimRewards checks for available biweekly gifts and claims them.
 func (b *Bot) claimRewards(ctx context.Context) error {
 	var hasRewards bool
@@ -17,7 +19,7 @@ func (b *Bot) claimRewards(ctx context.Context) error {
 	slog.Info("check duty free rewards")
 
 	// check "Free Reward" icon for the "Bonus" menu
-	hasRewards = utils.IsElementVisible(ctx, model.ICON_FREE_REWARDS, 10)
+	hasRewards = b.Page.Visible(ctx, model.ICON_FREE_REWARDS, 10*time.Second)
 
 	slog.Debug("rewards available", "has_rewards", hasRewards)
 
@@ -30,23 +32,21 @@ func (b *Bot) claimRewards(ctx context.Context) error {
 	slog.Debug("open pop-up window", "window", "bonus")
 
 	// open "Bonus" pop-up
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_MAIN_BONUS),
-	); err != nil {
+	if err := b.Page.ClickWait(ctx, model.BUTTON_MAIN_BONUS); err != nil {
 		slog.Warn("error in Bot.claimRewards > open bonus window", "error", err)
 
 		return err
 	}
 
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 
 	slog.Debug("navigate to Duty Free tab and claim rewards")
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_BONUS_DUTY_FREE_TAB),
-		chromedp.WaitReady(model.BUTTON_BONUS_CLAIM_GIFT, chromedp.ByQuery),
-		utils.ClickElement(model.BUTTON_BONUS_CLAIM_GIFT),
-		utils.ClickElement(model.BUTTON_COMMON_CLOSE_POPUP),
+	if err := runSteps(
+		func() error { return b.Page.ClickWait(ctx, model.BUTTON_BONUS_DUTY_FREE_TAB) },
+		func() error { return b.Page.WaitReady(ctx, model.BUTTON_BONUS_CLAIM_GIFT) },
+		func() error { return b.Page.ClickWait(ctx, model.BUTTON_BONUS_CLAIM_GIFT) },
+		func() error { return b.Page.ClickWait(ctx, model.BUTTON_COMMON_CLOSE_POPUP) },
 	); err != nil {
 		slog.Warn("error in Bot.claimRewards > claim gifts", "error", err)
 
@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/model"
+)
+
+func TestClaimRewards(t *testing.T) {
+	testCases := map[string]struct {
+		hasRewards      bool
+		expectedClaimed int
+	}{
+		"test01": {true, 1},
+		"test02": {false, 0},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, fakePage := newTestBot(t)
+			fakePage.Visibility[model.ICON_FREE_REWARDS] = testData.hasRewards
+
+			if err := b.claimRewards(t.Context()); err != nil {
+				t.Fatalf("claimRewards() returned error: %v", err)
+			}
+
+			if claimed := fakePage.Clicked(model.BUTTON_BONUS_CLAIM_GIFT); claimed != testData.expectedClaimed {
+				t.Errorf(`claimRewards() claimed '%d' times, expected '%d'`, claimed, testData.expectedClaimed)
+			}
+		})
+	}
+}
@@ -2,52 +2,103 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ashokhin/am4bot/internal/artifacts"
 	"github.com/ashokhin/am4bot/internal/config"
 	"github.com/ashokhin/am4bot/internal/io"
+	"github.com/ashokhin/am4bot/internal/ledger"
 	"github.com/ashokhin/am4bot/internal/metrics"
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+	"github.com/ashokhin/am4bot/internal/prices"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/chromedp/chromedp"
 )
 
+// VISIBILITY_TIMEOUT defines how long the bot waits for an optional element to become visible.
+const VISIBILITY_TIMEOUT time.Duration = 2 * time.Second
+
+var (
+	// ErrStopped is returned by the run which is stopped by Bot.Stop before all services are executed.
+	ErrStopped = errors.New("bot is stopped")
+	// ErrAllServicesFailed is returned by the run in which every executed service has failed,
+	// e.g. after the change of the game UI, so the run is counted as the failed one.
+	ErrAllServicesFailed = errors.New("all services have failed")
+)
+
 // Bot represents the automation bot with its configuration and state.
 type Bot struct {
 	Conf              *config.Config
+	Page              page.Page
 	chromeOpts        []chromedp.ExecAllocatorOption
+	browserOpts       browserOptions
 	AccountBalance    float64
 	BudgetMoney       BudgetType
 	PrometheusMetrics metrics.Metrics
 	Writer            *io.Writer
 	ProgressChan      chan struct{}
+	currentService    string
+	ledger            *ledger.Ledger
+	statusMu          sync.Mutex
+	status            Status
+	stopping          atomic.Bool
+	browser           *browser
+	artifactStore     *artifacts.Store
+	priceHistory      *prices.History
+	// fuelUsage holds the last checked holding and the burn rate of every fuel type
+	fuelUsage map[string]*fuelUsage
+	// runMu serializes runs and config reloads, so options aren't changed in the middle of the run
+	runMu    sync.Mutex
+	onReload func(changes []config.Change)
+	// confMu guards the replacement of Conf on reload for the readers outside of the run, e.g. the API
+	confMu sync.RWMutex
 }
 
 // Budget defines the budget allocations for different categories.
 type BudgetType struct {
-	Maintenance float64
-	Marketing   float64
-	Fuel        float64
+	Maintenance float64 `json:"maintenance"`
+	Marketing   float64 `json:"marketing"`
+	Fuel        float64 `json:"fuel"`
+	// Co2 is used if CO2 has its own budget percent, otherwise CO2 is bought from the Fuel budget
+	Co2 float64 `json:"co2,omitempty"`
 }
 
 // New creates a new Bot instance with the provided configuration and Prometheus registry.
-func New(conf *config.Config, registry *prometheus.Registry) Bot {
+// The registry of the account usually adds the "account" label to all metrics of the bot.
+func New(conf *config.Config, registry prometheus.Registerer) Bot {
 	metrics := metrics.New()
 	metrics.RegisterMetrics(registry)
 	metrics.StartTimeSeconds.SetToCurrentTime()
+	metrics.ConfigLastReloadSuccessful.Set(1)
 
 	// Setup Chrome options
 	opts := setupChromeOptions(conf)
 
+	warnUnknownServices(conf.Services)
+	warnIgnoredPriceStrategy(conf)
+	applySelectorOverrides(conf)
+
 	return Bot{
 		Conf:              conf,
+		Page:              page.NewChromedp(),
 		chromeOpts:        opts,
+		browserOpts:       newBrowserOptions(conf),
 		PrometheusMetrics: *metrics,
+		ledger:            newLedger(conf),
+		browser:           newPersistentBrowser(conf, opts),
+		artifactStore:     newArtifactStore(conf),
+		priceHistory:      newPriceHistory(conf),
 	}
 }
 
@@ -55,65 +106,306 @@ func New(conf *config.Config, registry *prometheus.Registry) Bot {
 func (b *Bot) ReloadBotConfig() error {
 
 	slog.Info("reloading Bot configuration")
-	// Setup Chrome options
-	b.chromeOpts = setupChromeOptions(b.Conf)
+	b.ledger = newLedger(b.Conf)
+	b.artifactStore = newArtifactStore(b.Conf)
+	b.priceHistory = newPriceHistory(b.Conf)
+
+	// the running browser is kept unless the options it's started with are changed
+	if browserOpts := newBrowserOptions(b.Conf); browserOpts != b.browserOpts {
+		slog.Info("browser options are changed, restart the browser")
+
+		b.browserOpts = browserOpts
+		b.chromeOpts = setupChromeOptions(b.Conf)
+		b.setupBrowser()
+	}
+
+	warnUnknownServices(b.Conf.Services)
+	warnIgnoredPriceStrategy(b.Conf)
+	applySelectorOverrides(b.Conf)
 
 	return nil
 }
 
-// Run executes the bot's main workflow, including authentication and service tasks.
-func (b *Bot) Run(ctx context.Context) error {
-	// reload config if changed
-	confChanged, err := b.Conf.ReloadConfigIfChanged()
+// SetReloadHandler sets the function which is called with the changed options
+// after the changed configuration has been applied to the bot.
+func (b *Bot) SetReloadHandler(handler func(changes []config.Change)) {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+
+	b.onReload = handler
+}
+
+// WatchedFiles returns the files of the configuration whose changes reload it.
+// It waits for the current run, because the run could reload the configuration.
+func (b *Bot) WatchedFiles() []string {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+
+	return b.Conf.WatchedFiles()
+}
+
+// ReloadConfig reloads the configuration if it has changed and applies it to the bot.
+// It waits for the current run, so options aren't changed in the middle of the run.
+func (b *Bot) ReloadConfig() ([]config.Change, error) {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+
+	return b.reloadConfig()
+}
+
+// reloadConfig reloads the configuration if it has changed, the caller must hold the run lock.
+// The previous configuration is kept if the changed one is invalid.
+func (b *Bot) reloadConfig() ([]config.Change, error) {
+	reloaded, changes, err := b.Conf.Reload()
 	if err != nil {
-		slog.Error("error reloading config", "error", err)
+		slog.Error("error reloading config, the previous config is kept", "account", b.Conf.Name, "error", err)
+
+		b.PrometheusMetrics.ConfigReloadFailuresTotal.Inc()
+		b.PrometheusMetrics.ConfigLastReloadSuccessful.Set(0)
+
+		return nil, err
 	}
 
-	// if config changed, update Chrome options
-	if confChanged {
-		slog.Info("updating Bot options due to config change")
-		b.ReloadBotConfig()
+	b.PrometheusMetrics.ConfigLastReloadSuccessful.Set(1)
+
+	if reloaded == nil {
+		return nil, nil
 	}
 
-	timeStart := time.Now()
-	var cdpLogger chromedp.ContextOption
+	// the reloaded configuration is the new instance, the previous one could still be read by the API
+	b.confMu.Lock()
+	b.Conf = reloaded
+	b.confMu.Unlock()
 
-	slog.Debug("create context with timeout", "timeout_seconds", b.Conf.TimeoutSeconds)
+	if len(changes) == 0 {
+		return nil, nil
+	}
 
-	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(b.Conf.TimeoutSeconds)*time.Second)
-	defer cancel()
+	var restart []string
 
-	allocatorCtx, cancel := chromedp.NewExecAllocator(timeoutCtx, b.chromeOpts...)
-	defer cancel()
+	for _, change := range changes {
+		if change.Restart {
+			restart = append(restart, change.Option)
+		}
+	}
 
-	if b.Conf.ChromeDebug {
-		cdpLogger = chromedp.WithDebugf(log.Printf)
-	} else {
-		cdpLogger = chromedp.WithLogf(log.Printf)
+	slog.Info("config has been reloaded", "account", b.Conf.Name, "changed", changes)
+
+	if len(restart) > 0 {
+		slog.Warn("changed options are applied only after the restart", "account", b.Conf.Name, "options", restart)
 	}
 
-	taskCtx, cancel := chromedp.NewContext(
-		allocatorCtx,
-		cdpLogger,
-	)
+	b.ReloadBotConfig()
+
+	if b.onReload != nil {
+		b.onReload(changes)
+	}
+
+	return changes, nil
+}
+
+// Config returns the current configuration of the bot.
+// Unlike Conf, it's safe to call concurrently with the config reload.
+// The returned configuration isn't modified by the reload, so it mustn't be modified by the caller.
+func (b *Bot) Config() *config.Config {
+	b.confMu.RLock()
+	defer b.confMu.RUnlock()
+
+	return b.Conf
+}
+
+// Stop stops the bot at the safe point: the current service is finished,
+// but the remaining services of the run and all further runs are skipped.
+// It doesn't wait for the current service.
+func (b *Bot) Stop() {
+	b.stopping.Store(true)
+}
+
+// runService executes the service in isolation: its error (or panic) is recorded
+// in Prometheus metrics and returned without affecting the other services.
+func (b *Bot) runService(ctx context.Context, s Service) (err error) {
+	serviceName := s.Name()
+	// initialize counter for the service, so it's exported even without errors
+	b.PrometheusMetrics.ServiceErrorsTotal.WithLabelValues(serviceName)
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in service %s: %v", serviceName, r)
+		}
+
+		if err != nil {
+			b.PrometheusMetrics.ServiceUp.WithLabelValues(serviceName).Set(0)
+			b.PrometheusMetrics.ServiceErrorsTotal.WithLabelValues(serviceName).Inc()
+
+			return
+		}
+
+		b.PrometheusMetrics.ServiceUp.WithLabelValues(serviceName).Set(1)
+		b.PrometheusMetrics.ServiceLastSuccessTimestamp.WithLabelValues(serviceName).SetToCurrentTime()
+	}()
+
+	slog.Debug("run service", "service", serviceName)
+
+	b.currentService = serviceName
+	defer func() { b.currentService = "" }()
+
+	return s.Run(ctx, b)
+}
+
+// orderServices returns the requested services sorted in the order of the "services" configuration option.
+// Requested services which are not configured are appended at the end.
+func (b *Bot) orderServices(services []string) []string {
+	var ordered []string
+
+	for _, serviceName := range b.Conf.Services {
+		if slices.Contains(services, serviceName) && !slices.Contains(ordered, serviceName) {
+			ordered = append(ordered, serviceName)
+		}
+	}
+
+	for _, serviceName := range services {
+		if !slices.Contains(ordered, serviceName) {
+			ordered = append(ordered, serviceName)
+		}
+	}
+
+	return ordered
+}
+
+// doClick clicks the element and waits until the page handles the click.
+// It's used for the navigation between pop-ups, so the error is logged in place.
+func (b *Bot) doClick(ctx context.Context, sel string) error {
+	if err := b.Page.ClickWait(ctx, sel); err != nil {
+		slog.Warn("error in Bot.doClick", "selector", sel, "error", err)
+
+		return err
+	}
+
+	return nil
+}
+
+// clickAll clicks the elements one by one and stops on the first error.
+func (b *Bot) clickAll(ctx context.Context, sels ...string) error {
+	for _, sel := range sels {
+		if err := b.Page.ClickWait(ctx, sel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runSteps executes the page actions one by one and stops on the first error.
+func runSteps(steps ...func() error) error {
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newLedger creates the purchases ledger if it's enabled in the configuration.
+func newLedger(conf *config.Config) *ledger.Ledger {
+	if conf.LedgerFile == "" {
+		slog.Debug("purchases ledger is disabled")
+
+		return nil
+	}
+
+	return ledger.New(conf.LedgerFile)
+}
+
+// applySelectorOverrides replaces the built-in CSS selectors with the ones from the "selectors_file".
+// Unknown selectors are only logged, so the typo doesn't stop the bot.
+func applySelectorOverrides(conf *config.Config) {
+	overrides := conf.SelectorOverrides()
+
+	if unknown := model.ApplySelectorOverrides(overrides); len(unknown) > 0 {
+		slog.Warn("unknown selectors in the selectors file are ignored", "file", conf.SelectorsFile, "selectors", unknown)
+	}
+
+	if len(overrides) > 0 {
+		slog.Info("CSS selectors have been overridden", "file", conf.SelectorsFile, "count", len(overrides))
+	}
+}
+
+// warnUnknownServices logs a warning for every configured service which is not registered.
+func warnUnknownServices(names []string) {
+	if unknown := UnknownServices(names); len(unknown) > 0 {
+		slog.Warn("unknown services in configuration", "services", unknown, "available_services", ServiceNames())
+	}
+}
+
+// Run executes the bot's main workflow, including authentication and all configured service tasks.
+func (b *Bot) Run(ctx context.Context) error {
+	return b.RunServices(ctx, b.Conf.Services)
+}
+
+// RunServices executes the bot's main workflow, including authentication
+// and the listed service tasks within a single browser session.
+// The result of the run is available with Status.
+func (b *Bot) RunServices(ctx context.Context, services []string) error {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+
+	result := b.startRun()
+	err := b.runServices(ctx, services, result)
+	b.finishRun(result, err)
+
+	return err
+}
+
+// runServices executes the listed services and records their results.
+func (b *Bot) runServices(ctx context.Context, services []string, result *RunResult) error {
+	if b.stopping.Load() {
+		return ErrStopped
+	}
+
+	// reload config if changed, e.g. the change has been missed by the file watcher
+	b.reloadConfig()
+
+	// selectors aren't overridden by the config reload of other accounts during the run
+	defer model.UseSelectors()()
+
+	timeStart := time.Now()
+
+	taskCtx, cancel, fresh, err := b.newSession(ctx, time.Duration(b.Conf.TimeoutSeconds)*time.Second)
+	if err != nil {
+		slog.Warn("error in Bot.Run > Bot.newSession", "error", err)
+
+		return err
+	}
 	defer cancel()
 
-	slog.Debug("run bot", "start_time", timeStart.UTC())
+	console := listenConsole(taskCtx)
+
+	slog.Debug("run bot", "start_time", timeStart.UTC(), "services", services)
 	slog.Info("start session")
-	slog.Debug("navigate", "url", b.Conf.Url)
 
-	if err := chromedp.Run(taskCtx,
+	if fresh {
+		slog.Debug("navigate", "url", b.Conf.Url)
+
 		// open URL to initialize session and cookies before authentication
-		chromedp.Navigate(b.Conf.Url),
-	); err != nil {
-		slog.Warn("error in Bot.Run navigate", "error", err)
+		if err := b.Page.Navigate(taskCtx, b.Conf.Url); err != nil {
+			slog.Warn("error in Bot.Run navigate", "error", err)
 
-		return err
+			return err
+		}
+	} else {
+		// the persistent browser keeps the page of the previous run
+		if err := b.Page.Reload(taskCtx); err != nil {
+			slog.Warn("error in Bot.Run > refresh page", "error", err)
+
+			return err
+		}
 	}
 
 	// perform authentication
 	if err := b.auth(taskCtx); err != nil {
 		slog.Warn("error in Bot.Run > Bot.auth", "error", err)
+		b.saveFailureArtifact(taskCtx, "auth", err, console)
 
 		return err
 	}
@@ -121,93 +413,163 @@ func (b *Bot) Run(ctx context.Context) error {
 	// perform money check
 	if err := b.money(taskCtx); err != nil {
 		slog.Warn("error in Bot.Run > Bot.money", "error", err)
+		b.saveFailureArtifact(taskCtx, "money", err, console)
 
 		return err
 	}
 
-	// iterate over configured services and execute them
-	for _, serviceName := range b.Conf.Services {
-		switch serviceName {
-		case "company_stats":
-			if err := b.companyStats(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.companyStats", "error", err)
+	var (
+		failedServices []string
+		serviceErrs    []error
+	)
 
-				return err
-			}
+	resolvedServices := resolveServices(b.orderServices(services))
 
-		case "alliance_stats":
-			if err := b.allianceStats(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.allianceStats", "error", err)
+	// execute requested services in the resolved order.
+	// A failed service doesn't stop the others
+	for _, s := range resolvedServices {
+		// stop the run if the session is timed out or cancelled
+		if err := taskCtx.Err(); err != nil {
+			slog.Warn("error in Bot.Run > session is closed", "service", s.Name(), "error", err)
 
-				return err
-			}
+			return err
+		}
 
-		case "claim_rewards":
-			if err := b.claimRewards(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.claimRewards", "error", err)
+		// stop between services, so no purchase is interrupted
+		if b.stopping.Load() {
+			slog.Warn("bot is stopped, skip remaining services", "service", s.Name())
 
-				return err
-			}
+			return ErrStopped
+		}
 
-		case "staff_morale":
-			if err := b.staffMorale(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.staffMorale", "error", err)
+		err := b.runService(taskCtx, s)
+		result.addService(s.Name(), err)
 
-				return err
-			}
+		if err != nil {
+			slog.Warn("error in Bot.Run > Bot.runService", "service", s.Name(), "error", err)
+
+			failedServices = append(failedServices, s.Name())
+			serviceErrs = append(serviceErrs, fmt.Errorf("%s: %w", s.Name(), err))
+			b.saveFailureArtifact(taskCtx, s.Name(), err, console)
 
-		case "hubs":
-			if err := b.hubs(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.hubs", "error", err)
+			// reset the page state (e.g. opened pop-ups) which the failed service could leave
+			if err := b.Page.Reload(taskCtx); err != nil {
+				slog.Warn("error in Bot.Run > refresh page after failed service", "error", err)
 
 				return err
 			}
+		}
+	}
 
-		case "buy_fuel":
-			if err := b.fuel(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.fuel", "error", err)
+	// calculate total duration for Prometheus metric and logging
+	duration := time.Since(timeStart)
 
-				return err
-			}
+	slog.Info("run complete", "elapsed_time", fmt.Sprint(duration), "failed_services", failedServices)
 
-		case "marketing":
-			if err := b.marketingCompanies(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.marketingCompanies", "error", err)
+	b.PrometheusMetrics.DurationSeconds.Set(duration.Seconds())
 
-				return err
-			}
+	return servicesError(serviceErrs, len(resolvedServices))
+}
 
-		case "ac_maintenance":
-			if err := b.maintenance(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.maintenance", "error", err)
+// servicesError returns ErrAllServicesFailed with the errors of services if every executed service has failed.
+// The run with some failed services is successful, the failed services are reported in the run result.
+func servicesError(serviceErrs []error, executed int) error {
+	if executed == 0 || len(serviceErrs) < executed {
+		return nil
+	}
 
-				return err
-			}
-		case "depart":
-			if err := b.depart(taskCtx); err != nil {
-				slog.Warn("error in Bot.Run > Bot.depart", "error", err)
+	return fmt.Errorf("%w: %w", ErrAllServicesFailed, errors.Join(serviceErrs...))
+}
 
-				return err
-			}
+// newSession returns the browser context for the run limited by the timeout ("timeout_seconds" for runs).
+// A new browser is started for every run unless the persistent browser is enabled.
+// The fresh result reports that the page is not opened in the browser yet.
+func (b *Bot) newSession(ctx context.Context, timeout time.Duration) (taskCtx context.Context, cancel context.CancelFunc, fresh bool, err error) {
+	slog.Debug("create context with timeout", "timeout", timeout)
 
-		default:
-			slog.Warn("unknown service", "service", serviceName,
-				"available_services",
-				[]string{"company_stats", "staff_morale", "alliance_stats", "hubs", "buy_fuel", "depart", "marketing", "ac_maintenance"})
+	if b.Conf.ChromeRemoteUrl != "" {
+		if err := checkRemoteBrowser(ctx, b.Conf.ChromeRemoteUrl); err != nil {
+			return nil, nil, false, err
 		}
 	}
 
-	// calculate total duration for Prometheus metric and logging
-	duration := time.Since(timeStart)
+	if b.browser == nil {
+		timeoutCtx, cancelTimeout := context.WithTimeout(ctx, timeout)
+		allocatorCtx, cancelAllocator := newAllocator(b.Conf, b.chromeOpts)(timeoutCtx)
+		taskCtx, cancelTask := chromedp.NewContext(allocatorCtx, chromeLogger(b.Conf))
 
-	slog.Info("run complete", "elapsed_time", fmt.Sprint(duration))
+		return taskCtx, func() {
+			cancelTask()
+			cancelAllocator()
+			cancelTimeout()
+		}, true, nil
+	}
 
-	b.PrometheusMetrics.DurationSeconds.Set(duration.Seconds())
+	tabCtx, fresh, err := b.browser.tab()
+	if err != nil {
+		return nil, nil, false, err
+	}
 
-	return nil
+	// cancelling of the run context doesn't close the persistent tab
+	taskCtx, cancelTimeout := context.WithTimeout(tabCtx, timeout)
+	// stop the run when the caller's context is cancelled as well
+	stop := context.AfterFunc(ctx, cancelTimeout)
+
+	return taskCtx, func() {
+		stop()
+		cancelTimeout()
+	}, fresh, nil
+}
+
+// Close stops the persistent browser if it's running.
+func (b *Bot) Close() {
+	if b.browser != nil {
+		b.browser.close()
+	}
+}
+
+// setupBrowser stops the persistent browser, so the new one is started with the actual options.
+func (b *Bot) setupBrowser() {
+	b.Close()
+	b.browser = newPersistentBrowser(b.Conf, b.chromeOpts)
+}
+
+// newPersistentBrowser creates the persistent browser if it's enabled in the configuration.
+func newPersistentBrowser(conf *config.Config, opts []chromedp.ExecAllocatorOption) *browser {
+	if !conf.ChromePersistent {
+		return nil
+	}
+
+	return newBrowser(newAllocator(conf, opts), chromeLogger(conf))
+}
+
+// newAllocator returns the function which creates the browser context: it connects to the remote
+// browser if the "chrome_remote_url" is set, otherwise it starts the local Chrome with the options.
+func newAllocator(conf *config.Config, opts []chromedp.ExecAllocatorOption) func(ctx context.Context) (context.Context, context.CancelFunc) {
+	if remoteUrl := conf.ChromeRemoteUrl; remoteUrl != "" {
+		return func(ctx context.Context) (context.Context, context.CancelFunc) {
+			return chromedp.NewRemoteAllocator(ctx, remoteUrl)
+		}
+	}
+
+	return func(ctx context.Context) (context.Context, context.CancelFunc) {
+		return chromedp.NewExecAllocator(ctx, opts...)
+	}
 }
 
-func getChromedpUserDataDir(appName string) string {
+// chromeLogger returns the logging option for the chromedp context.
+func chromeLogger(conf *config.Config) chromedp.ContextOption {
+	// set the 'chrome_debug: true' config for detailed Chrome/Chromium debugging logs
+	if conf.ChromeDebug {
+		return chromedp.WithDebugf(log.Printf)
+	}
+
+	return chromedp.WithLogf(log.Printf)
+}
+
+// getChromedpUserDataDir returns the Chrome user data dir of the account, so every account keeps its own session.
+// The default account uses the same dir as before the accounts were introduced.
+func getChromedpUserDataDir(appName string, accountName string) string {
 	cacheDir, err := os.UserCacheDir()
 	if err != nil {
 		// fallback: next to binary or in temp dir if current directory is not writable
@@ -216,6 +578,10 @@ func getChromedpUserDataDir(appName string) string {
 
 	dir := filepath.Join(cacheDir, appName, "chromedp-user-data")
 
+	if accountName != "" && accountName != config.DEFAULT_ACCOUNT_NAME {
+		dir = filepath.Join(cacheDir, appName, "accounts", accountName, "chromedp-user-data")
+	}
+
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		slog.Warn("failed to create user data dir", "error", err)
 		return ""
@@ -227,7 +593,12 @@ func getChromedpUserDataDir(appName string) string {
 }
 
 // setupChromeOptions configures Chrome options based on the provided configuration.
+// The remote browser is started with its own options, so no options and no user data dir are set up for it.
 func setupChromeOptions(conf *config.Config) []chromedp.ExecAllocatorOption {
+	if conf.ChromeRemoteUrl != "" {
+		return nil
+	}
+
 	// Setup Chrome options
 	opts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.NoFirstRun,
@@ -237,7 +608,7 @@ func setupChromeOptions(conf *config.Config) []chromedp.ExecAllocatorOption {
 		chromedp.Flag("headless", conf.ChromeHeadless),
 		chromedp.Flag("start-maximized", true),
 		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.UserDataDir(getChromedpUserDataDir("am4bot")),
+		chromedp.UserDataDir(getChromedpUserDataDir("am4bot", conf.Name)),
 	)
 
 	return opts
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/artifacts"
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/metrics"
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+	"github.com/creasty/defaults"
+	"github.com/prometheus/common/promslog"
+)
+
+// newTestBot creates a new Bot instance with the default configuration and the fake page.
+func newTestBot(t *testing.T) (*Bot, *page.Fake) {
+	t.Helper()
+
+	conf := &config.Config{}
+
+	if err := defaults.Set(conf); err != nil {
+		t.Fatalf("defaults.Set() returned error: %v", err)
+	}
+
+	fakePage := page.NewFake()
+
+	b := &Bot{
+		Conf:              conf,
+		Page:              fakePage,
+		PrometheusMetrics: *metrics.New(),
+	}
+
+	return b, fakePage
+}
+
+func TestStop(t *testing.T) {
+	b, fakePage := newTestBot(t)
+
+	b.Stop()
+
+	if err := b.RunServices(t.Context(), []string{"depart"}); !errors.Is(err, ErrStopped) {
+		t.Errorf(`RunServices() returned error '%v', expected '%v'`, err, ErrStopped)
+	}
+
+	if len(fakePage.Clicks) != 0 {
+		t.Errorf(`stopped bot made clicks '%+v'`, fakePage.Clicks)
+	}
+
+	if status := b.Status(); status.LastRun == nil || status.LastRun.Success {
+		t.Errorf(`Status() returned '%+v', expected the failed run`, status)
+	}
+}
+
+func TestSaveFailureArtifact(t *testing.T) {
+	b, fakePage := newTestBot(t)
+	b.artifactStore = artifacts.New(t.TempDir(), 0)
+
+	fakePage.URLs = []string{"https://www.airlinemanager.com/"}
+	fakePage.Texts[model.ELEMENT_COMMON_POPUP] = `<div id="popup">Not enough fuel</div>`
+
+	console := &consoleLog{}
+
+	for i := range CONSOLE_LOG_LINES + 1 {
+		console.add("console error: %d", i)
+	}
+
+	b.saveFailureArtifact(t.Context(), "depart", errors.New("not enough fuel"), console)
+
+	a, err := b.Artifacts().Latest()
+	if err != nil {
+		t.Fatalf("Store.Latest() returned error: %v", err)
+	}
+
+	if a.Service != "depart" || a.Error != "not enough fuel" || a.URL != "https://www.airlinemanager.com/" {
+		t.Errorf(`artifact '%+v' doesn't describe the failure`, a)
+	}
+
+	if !slices.Equal(a.Files, []string{artifacts.HTML_FILE, artifacts.INFO_FILE}) {
+		t.Errorf(`artifact files '%+v', expected '%+v'`, a.Files, []string{artifacts.HTML_FILE, artifacts.INFO_FILE})
+	}
+
+	if len(a.Console) != CONSOLE_LOG_LINES || !strings.HasSuffix(a.Console[0], "console error: 1") {
+		t.Errorf(`artifact console '%+v' doesn't contain the last %d lines`, a.Console, CONSOLE_LOG_LINES)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	b, _ := newTestBot(t)
+
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(confPath, []byte("cron_schedule: \"*/5 * * * *\"\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	conf, err := config.New(confPath)
+	if err != nil {
+		t.Fatalf("config.New() returned error: %v", err)
+	}
+
+	conf.PromslogConfig = &promslog.Config{Level: promslog.NewLevel()}
+	b.Conf = conf
+
+	var handled []config.Change
+
+	b.SetReloadHandler(func(changes []config.Change) {
+		handled = changes
+	})
+
+	if err := os.WriteFile(confPath, []byte("cron_schedule: \"*/10 * * * *\"\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	changes, err := b.ReloadConfig()
+	if err != nil {
+		t.Fatalf("ReloadConfig() returned error: %v", err)
+	}
+
+	expected := []config.Change{{Option: "cron_schedule"}}
+
+	if !slices.Equal(changes, expected) || !slices.Equal(handled, expected) {
+		t.Errorf(`changes '%v' and handled changes '%v', expected '%v'`, changes, handled, expected)
+	}
+
+	if err := os.WriteFile(confPath, []byte("cron_schedule: \"*/10 * * *\"\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	if _, err := b.ReloadConfig(); err == nil || b.Conf.CronSchedule != "*/10 * * * *" {
+		t.Errorf(`ReloadConfig() of the invalid config returned '%v' with the schedule '%s', expected the error and the previous schedule`, err, b.Conf.CronSchedule)
+	}
+}
+
+func TestServicesError(t *testing.T) {
+	serviceErr := errors.New("element not found")
+
+	testCases := map[string]struct {
+		serviceErrs []error
+		executed    int
+		expectedErr error
+	}{
+		"test01": {nil, 3, nil},
+		"test02": {[]error{serviceErr}, 3, nil},
+		"test03": {[]error{serviceErr, serviceErr, serviceErr}, 3, ErrAllServicesFailed},
+		"test04": {nil, 0, nil},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			err := servicesError(testData.serviceErrs, testData.executed)
+
+			if !errors.Is(err, testData.expectedErr) || (err == nil) != (testData.expectedErr == nil) {
+				t.Errorf(`servicesError() returned '%v', expected '%v'`, err, testData.expectedErr)
+			}
+
+			if err != nil && !errors.Is(err, serviceErr) {
+				t.Errorf(`servicesError() returned '%v' without the errors of services`, err)
+			}
+		})
+	}
+}
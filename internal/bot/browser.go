@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/chromedp/cdproto/inspector"
+	"github.com/chromedp/chromedp"
+)
+
+// BROWSER_HEALTH_TIMEOUT defines how long the bot waits for the persistent browser to respond.
+const BROWSER_HEALTH_TIMEOUT time.Duration = 5 * time.Second
+
+// browser keeps a single Chrome process and tab alive between runs.
+// The browser is restarted if it's crashed or disconnected.
+type browser struct {
+	mu         sync.Mutex
+	allocate   func(ctx context.Context) (context.Context, context.CancelFunc)
+	ctxOptions []chromedp.ContextOption
+	tabCtx     context.Context
+	cancel     context.CancelFunc
+	crashed    atomic.Bool
+}
+
+// browserOptions holds the options of the configuration which the browser is started with.
+// The browser is restarted on the config reload only if they are changed.
+type browserOptions struct {
+	headless   bool
+	debug      bool
+	persistent bool
+	remoteUrl  string
+	// account defines the user data dir of the browser
+	account string
+}
+
+// newBrowserOptions returns the browser options of the configuration.
+func newBrowserOptions(conf *config.Config) browserOptions {
+	return browserOptions{
+		headless:   conf.ChromeHeadless,
+		debug:      conf.ChromeDebug,
+		persistent: conf.ChromePersistent,
+		remoteUrl:  conf.ChromeRemoteUrl,
+		account:    conf.Name,
+	}
+}
+
+// newBrowser creates a new browser manager. The browser is started on the first use.
+func newBrowser(allocate func(ctx context.Context) (context.Context, context.CancelFunc), ctxOptions ...chromedp.ContextOption) *browser {
+	return &browser{
+		allocate:   allocate,
+		ctxOptions: ctxOptions,
+	}
+}
+
+// tab returns the context of the browser tab. The browser is started if it's not running yet
+// or the previous one is crashed or disconnected, in this case fresh is true.
+func (br *browser) tab() (tabCtx context.Context, fresh bool, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	if br.tabCtx != nil {
+		if br.healthy() {
+			return br.tabCtx, false, nil
+		}
+
+		slog.Warn("browser is crashed or disconnected, restart it")
+
+		br.closeLocked()
+	}
+
+	slog.Info("start persistent browser")
+
+	allocatorCtx, cancelAllocator := br.allocate(context.Background())
+	tabCtx, cancelTab := chromedp.NewContext(allocatorCtx, br.ctxOptions...)
+
+	// start the browser and open the tab
+	if err := chromedp.Run(tabCtx); err != nil {
+		slog.Warn("error in browser.tab > start browser", "error", err)
+
+		cancelTab()
+		cancelAllocator()
+
+		return nil, false, err
+	}
+
+	br.crashed.Store(false)
+
+	chromedp.ListenTarget(tabCtx, func(ev any) {
+		switch ev.(type) {
+		case *inspector.EventTargetCrashed, *inspector.EventDetached:
+			br.crashed.Store(true)
+		}
+	})
+
+	br.tabCtx = tabCtx
+	br.cancel = func() {
+		cancelTab()
+		cancelAllocator()
+	}
+
+	return tabCtx, true, nil
+}
+
+// healthy reports whether the browser tab is alive and responds.
+func (br *browser) healthy() bool {
+	if br.crashed.Load() || br.tabCtx.Err() != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(br.tabCtx, BROWSER_HEALTH_TIMEOUT)
+	defer cancel()
+
+	var result int
+
+	if err := chromedp.Run(ctx, chromedp.Evaluate(`1`, &result)); err != nil {
+		slog.Warn("error in browser.healthy", "error", err)
+
+		return false
+	}
+
+	return true
+}
+
+// close closes the tab and stops the browser.
+func (br *browser) close() {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	br.closeLocked()
+}
+
+// closeLocked stops the browser, the caller must hold the lock.
+func (br *browser) closeLocked() {
+	if br.cancel != nil {
+		br.cancel()
+	}
+
+	br.tabCtx = nil
+	br.cancel = nil
+}
+
+// checkRemoteBrowser checks that the remote browser responds on its DevTools endpoint.
+func checkRemoteBrowser(ctx context.Context, remoteUrl string) error {
+	versionUrl, err := devToolsVersionUrl(remoteUrl)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, BROWSER_HEALTH_TIMEOUT)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, versionUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote browser is unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote browser is unavailable: DevTools endpoint returned status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// devToolsVersionUrl returns the HTTP URL of the "/json/version" DevTools endpoint for the browser URL.
+func devToolsVersionUrl(remoteUrl string) (string, error) {
+	u, err := url.Parse(remoteUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid chrome_remote_url %q: %w", remoteUrl, err)
+	}
+
+	switch u.Scheme {
+	case "ws", "http":
+		u.Scheme = "http"
+	case "wss", "https":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("invalid chrome_remote_url %q: unsupported scheme %q", remoteUrl, u.Scheme)
+	}
+
+	// the query keeps the access token of the browser services like browserless
+	u.Path = "/json/version"
+	u.Fragment = ""
+
+	return u.String(), nil
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/prometheus/common/promslog"
+)
+
+func TestDevToolsVersionUrl(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected string
+		isValid  bool
+	}{
+		"test01": {"ws://chrome:9222", "http://chrome:9222/json/version", true},
+		"test02": {"ws://chrome:9222/devtools/browser/b0b0", "http://chrome:9222/json/version", true},
+		"test03": {"wss://browserless.example.com?token=secret", "https://browserless.example.com/json/version?token=secret", true},
+		"test04": {"http://127.0.0.1:9222/", "http://127.0.0.1:9222/json/version", true},
+		"test05": {"chrome:9222", "", false},
+		"test06": {"ftp://chrome:9222", "", false},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			result, err := devToolsVersionUrl(testData.input)
+
+			if (err == nil) != testData.isValid {
+				t.Fatalf(`devToolsVersionUrl(%q) returned error '%v', expected valid: %v`, testData.input, err, testData.isValid)
+			}
+
+			if result != testData.expected {
+				t.Errorf(`devToolsVersionUrl(%q) returned '%s', expected '%s'`, testData.input, result, testData.expected)
+			}
+		})
+	}
+}
+
+func TestCheckRemoteBrowser(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /json/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Browser":"HeadlessChrome/140.0.0.0","webSocketDebuggerUrl":"ws://127.0.0.1/devtools/browser/b0b0"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	wsUrl := "ws://" + strings.TrimPrefix(server.URL, "http://")
+
+	if err := checkRemoteBrowser(t.Context(), wsUrl); err != nil {
+		t.Errorf(`checkRemoteBrowser(%q) returned error: %v`, wsUrl, err)
+	}
+
+	server.Close()
+
+	if err := checkRemoteBrowser(t.Context(), wsUrl); err == nil {
+		t.Errorf(`checkRemoteBrowser(%q) didn't return error for the stopped browser`, wsUrl)
+	}
+}
+
+func TestReloadBrowser(t *testing.T) {
+	b, _ := newTestBot(t)
+
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(confPath, []byte("chrome_persistent: true\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	conf, err := config.New(confPath)
+	if err != nil {
+		t.Fatalf("config.New() returned error: %v", err)
+	}
+
+	conf.PromslogConfig = &promslog.Config{Level: promslog.NewLevel()}
+	b.Conf = conf
+	b.browserOpts = newBrowserOptions(conf)
+	b.browser = newPersistentBrowser(conf, nil)
+
+	testCases := []struct {
+		content         string
+		expectedRestart bool
+	}{
+		{"chrome_persistent: true\ncron_schedule: \"*/10 * * * *\"\n", false},
+		{"chrome_persistent: true\nchrome_headless: false\n", true},
+		{"chrome_persistent: true\nchrome_headless: false\nlog_level: debug\n", false},
+	}
+
+	for _, testData := range testCases {
+		previous := b.browser
+
+		if err := os.WriteFile(confPath, []byte(testData.content), 0600); err != nil {
+			t.Fatalf("os.WriteFile() returned error: %v", err)
+		}
+
+		if _, err := b.ReloadConfig(); err != nil {
+			t.Fatalf("ReloadConfig() returned error: %v", err)
+		}
+
+		if restarted := b.browser != previous; restarted != testData.expectedRestart {
+			t.Errorf(`browser restarted: %v after the reload of '%s', expected %v`, restarted, testData.content, testData.expectedRestart)
+		}
+	}
+}
+
+func TestSetupChromeOptions(t *testing.T) {
+	testCases := map[string]struct {
+		remoteUrl       string
+		expectedUserDir bool
+	}{
+		"test01": {"", true},
+		"test02": {"ws://chrome:9222", false},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			t.Setenv("XDG_CACHE_HOME", cacheDir)
+
+			opts := setupChromeOptions(&config.Config{Name: "first", ChromeRemoteUrl: testData.remoteUrl})
+
+			_, err := os.Stat(filepath.Join(cacheDir, "am4bot", "accounts", "first", "chromedp-user-data"))
+
+			if (len(opts) > 0) != testData.expectedUserDir || (err == nil) != testData.expectedUserDir {
+				t.Errorf(`setupChromeOptions() returned %d options and user data dir error '%v', expected the local browser options: %v`,
+					len(opts), err, testData.expectedUserDir)
+			}
+		})
+	}
+}
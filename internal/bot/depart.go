@@ -6,10 +6,12 @@ import (
 	"math"
 
 	"github.com/ashokhin/am4bot/internal/model"
-	"github.com/ashokhin/am4bot/internal/utils"
-	"github.com/chromedp/chromedp"
 )
 
+func init() {
+	RegisterService(service{name: "depart", dependencies: []string{"ac_maintenance", "buy_fuel"}, run: (*Bot).depart})
+}
+
 // depart handles the departure of all available aircraft from the fleet.
 func (b *Bot) depart(ctx context.Context) error {
 	slog.Info("depart all available aircraft")
@@ -27,7 +29,7 @@ func (b *Bot) depart(ctx context.Context) error {
 		slog.Debug("depart available aircraft", "ready to depart", aircraftReadyForDepart, "depart retries", maxRetries)
 
 		// click the "Depart All" button
-		utils.DoClickElement(ctx, model.BUTTON_FI_DEPART_ALL)
+		b.doClick(ctx, model.BUTTON_FI_DEPART_ALL)
 		// get the number of aircraft still ready for departure
 		availableAfterDepart = b.getReadyForDepart(ctx)
 
@@ -48,11 +50,8 @@ func (b *Bot) depart(ctx context.Context) error {
 
 // getReadyForDepart retrieves the number of aircraft ready for departure from the fleet interface.
 func (b *Bot) getReadyForDepart(ctx context.Context) int {
-	var readyForDepart int
-
-	if err := chromedp.Run(ctx,
-		utils.GetIntFromElement(model.TEXT_FI_DEPART_AMOUNT, &readyForDepart),
-	); err != nil {
+	readyForDepart, err := b.Page.Int(ctx, model.TEXT_FI_DEPART_AMOUNT)
+	if err != nil {
 		slog.Debug("the 'Depart' amount element not found, assuming 0 ready for depart", "error", err)
 
 		return 0
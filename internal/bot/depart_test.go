@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+)
+
+func TestDepart(t *testing.T) {
+	testCases := map[string]struct {
+		readyForDepart int
+		departPerClick int
+		expectedClicks int
+	}{
+		"test01": {0, 20, 0},
+		"test02": {15, 20, 1},
+		"test03": {45, 20, 3},
+		// grounded aircraft are never departed, the number of retries is limited
+		"test04": {30, 0, 3},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			ready := testData.readyForDepart
+
+			b, fakePage := newTestBot(t)
+			fakePage.Texts[model.TEXT_FI_DEPART_AMOUNT] = strconv.Itoa(ready)
+			fakePage.OnClick[model.BUTTON_FI_DEPART_ALL] = func(f *page.Fake) {
+				ready = max(ready-testData.departPerClick, 0)
+				f.Texts[model.TEXT_FI_DEPART_AMOUNT] = strconv.Itoa(ready)
+			}
+
+			if err := b.depart(t.Context()); err != nil {
+				t.Fatalf("depart() returned error: %v", err)
+			}
+
+			if clicks := fakePage.Clicked(model.BUTTON_FI_DEPART_ALL); clicks != testData.expectedClicks {
+				t.Errorf(`depart() clicked 'Depart' '%d' times, expected '%d'`, clicks, testData.expectedClicks)
+			}
+		})
+	}
+}
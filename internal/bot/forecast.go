@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/prices"
+)
+
+const (
+	// BURN_RATE_SMOOTHING defines the weight of the last interval in the estimated burn rate (exponential moving average).
+	BURN_RATE_SMOOTHING float64 = 0.3
+	// BURN_RATE_MIN_INTERVAL defines the shortest interval between checks used for the estimation,
+	// e.g. the manual run right after the scheduled one doesn't change the estimation.
+	BURN_RATE_MIN_INTERVAL time.Duration = time.Minute
+)
+
+// fuelUsage holds the last checked holding of the fuel type and its estimated burn rate.
+type fuelUsage struct {
+	checkedAt time.Time
+	holding   float64
+	// rate is the consumption per hour
+	rate    float64
+	samples int
+}
+
+// updateBurnRate estimates the consumption per hour of the fuel type by the holding deltas between checks.
+// Purchases made by the bot are added to the previous holding, so they aren't subtracted from the consumption.
+func (b *Bot) updateBurnRate(fuelStruct *model.Fuel, now time.Time) {
+	if b.fuelUsage == nil {
+		b.fuelUsage = make(map[string]*fuelUsage)
+	}
+
+	usage, ok := b.fuelUsage[fuelStruct.FuelType]
+	if !ok {
+		b.fuelUsage[fuelStruct.FuelType] = &fuelUsage{checkedAt: now, holding: fuelStruct.Holding}
+
+		return
+	}
+
+	if elapsed := now.Sub(usage.checkedAt); elapsed >= BURN_RATE_MIN_INTERVAL {
+		consumed := usage.holding - fuelStruct.Holding
+
+		if consumed < 0 {
+			// e.g. the fuel has been bought manually
+			slog.Debug("holding has been increased outside of the bot, burn rate isn't updated", "type", fuelStruct.FuelType)
+		} else if rate := consumed / elapsed.Hours(); usage.samples == 0 {
+			usage.rate = rate
+			usage.samples++
+		} else {
+			usage.rate = BURN_RATE_SMOOTHING*rate + (1-BURN_RATE_SMOOTHING)*usage.rate
+			usage.samples++
+		}
+
+		usage.checkedAt = now
+		usage.holding = fuelStruct.Holding
+	}
+
+	if usage.samples == 0 {
+		return
+	}
+
+	fuelStruct.BurnRate = usage.rate
+
+	b.PrometheusMetrics.FuelBurnRate.WithLabelValues(fuelStruct.FuelType).Set(usage.rate)
+
+	if usage.rate > 0 {
+		b.PrometheusMetrics.FuelHoursUntilEmpty.WithLabelValues(fuelStruct.FuelType).Set(fuelStruct.Holding / usage.rate)
+	}
+
+	slog.Debug("fuel burn rate", "type", fuelStruct.FuelType, "rate", usage.rate, "samples", usage.samples)
+}
+
+// addBoughtFuel adds the bought amount to the last checked holding, so the purchase isn't treated as the negative consumption.
+func (b *Bot) addBoughtFuel(fuelType string, amount float64) {
+	if usage, ok := b.fuelUsage[fuelType]; ok {
+		usage.holding += amount
+	}
+}
+
+// forecastFillPercent returns the level of the tank (in percents) which lasts until the next expected price
+// not higher than the maximal price, or zero if the current holding lasts long enough or the forecast isn't available.
+// The forecast is disabled by "fuel_reserve_hours" <= 0 even if the price history is recorded.
+func (b *Bot) forecastFillPercent(fuelStruct *model.Fuel, maxPrice float64) float64 {
+	if b.Conf.FuelReserveHours <= 0 || fuelStruct.BurnRate <= 0 || fuelStruct.Capacity <= 0 {
+		return 0
+	}
+
+	reserveHours := b.fuelReserveHours(fuelStruct.FuelType, maxPrice)
+	needHolding := fuelStruct.BurnRate * reserveHours
+
+	slog.Debug("fuel forecast", "type", fuelStruct.FuelType, "burn_rate", fuelStruct.BurnRate,
+		"reserve_hours", reserveHours, "need_holding", needHolding, "holding", fuelStruct.Holding)
+
+	if needHolding <= fuelStruct.Holding {
+		return 0
+	}
+
+	return min(needHolding/fuelStruct.Capacity*100, 100)
+}
+
+// fuelReserveHours returns how many hours the tank must last: the average wait for the next cheap price
+// from the price history or "fuel_reserve_hours" if there are not enough observed prices.
+func (b *Bot) fuelReserveHours(fuelType string, maxPrice float64) float64 {
+	historyConf := b.Conf.PriceHistory
+
+	if b.priceHistory == nil {
+		return b.Conf.FuelReserveHours
+	}
+
+	observations, err := b.priceHistory.Read(fuelType, time.Now().Add(-time.Duration(historyConf.Days)*24*time.Hour))
+	if err != nil {
+		slog.Warn("error in Bot.fuelReserveHours > History.Read", "file", b.priceHistory.FilePath(), "error", err)
+
+		return b.Conf.FuelReserveHours
+	}
+
+	if len(observations) < historyConf.MinSamples {
+		return b.Conf.FuelReserveHours
+	}
+
+	wait, ok := prices.ExpectedWait(observations, maxPrice)
+	if !ok {
+		return b.Conf.FuelReserveHours
+	}
+
+	return wait.Hours()
+}
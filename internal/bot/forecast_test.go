@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/model"
+)
+
+func TestUpdateBurnRate(t *testing.T) {
+	b, _ := newTestBot(t)
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	checks := []struct {
+		after            time.Duration
+		holding          float64
+		bought           float64
+		expectedBurnRate float64
+	}{
+		// the first check, the rate is unknown
+		{0, 50000, 0, 0},
+		// 10000 per 2 hours
+		{2 * time.Hour, 40000, 30000, 5000},
+		// 70000 - 60000 per hour, the purchase isn't the consumption
+		{3 * time.Hour, 60000, 0, 6500},
+		// too short interval, the rate isn't changed
+		{3*time.Hour + 30*time.Second, 59000, 0, 6500},
+		// the holding has been increased outside of the bot
+		{4 * time.Hour, 90000, 0, 6500},
+	}
+
+	for i, check := range checks {
+		fuel := model.Fuel{FuelType: "fuel", Holding: check.holding, Capacity: 100000}
+
+		b.updateBurnRate(&fuel, start.Add(check.after))
+
+		if fuel.BurnRate != check.expectedBurnRate {
+			t.Errorf(`check %d: burn rate '%v', expected '%v'`, i, fuel.BurnRate, check.expectedBurnRate)
+		}
+
+		if check.bought > 0 {
+			b.addBoughtFuel("fuel", check.bought)
+		}
+	}
+}
+
+func TestBuyFuelTypeForecast(t *testing.T) {
+	testCases := map[string]struct {
+		fuel           model.Fuel
+		reserveHours   float64
+		expectedAmount string
+	}{
+		// 10 hours of 5000 per hour, buy up to 50%
+		"test01": {model.Fuel{FuelType: "fuel", Price: 800, Holding: 30000, Capacity: 100000, BurnRate: 5000}, 10, "20000"},
+		// the holding lasts long enough
+		"test02": {model.Fuel{FuelType: "fuel", Price: 800, Holding: 60000, Capacity: 100000, BurnRate: 5000}, 10, ""},
+		// the forecast is disabled
+		"test03": {model.Fuel{FuelType: "fuel", Price: 800, Holding: 30000, Capacity: 100000, BurnRate: 5000}, 0, ""},
+		// the burn rate is unknown
+		"test04": {model.Fuel{FuelType: "fuel", Price: 800, Holding: 30000, Capacity: 100000}, 10, ""},
+		// the good price fills the tank fully
+		"test05": {model.Fuel{FuelType: "fuel", Price: 400, Holding: 30000, Capacity: 100000, BurnRate: 5000}, 10, "70000"},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, fakePage := newTestBot(t)
+			b.Conf.FuelReserveHours = testData.reserveHours
+			b.BudgetMoney.Fuel = 1000000
+
+			if err := b.buyFuelType(t.Context(), &testData.fuel); err != nil {
+				t.Fatalf("buyFuelType(%+v) returned error: %v", testData.fuel, err)
+			}
+
+			if amount := fakePage.Values[model.TEXT_FIELD_FUEL_AMOUNT]; amount != testData.expectedAmount {
+				t.Errorf(`buyFuelType(%+v) bought '%s', expected '%s'`, testData.fuel, amount, testData.expectedAmount)
+			}
+		})
+	}
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/ashokhin/am4bot/internal/model"
-	"github.com/ashokhin/am4bot/internal/utils"
-	"github.com/chromedp/chromedp"
+	"github.com/ashokhin/am4bot/internal/page"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+func init() {
+	RegisterService(service{name: "buy_fuel", run: (*Bot).fuel})
+}
+
 // FUEL_MINIMUM_AMOUNT defines the minimum amount of fuel that system allows to be purchased
 const FUEL_MINIMUM_AMOUNT float64 = 1000.00
 
@@ -28,8 +34,8 @@ func (b *Bot) fuel(ctx context.Context) error {
 	}
 
 	// open fuel window
-	utils.DoClickElement(ctx, model.BUTTON_MAIN_FUEL)
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	b.doClick(ctx, model.BUTTON_MAIN_FUEL)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 
 	// iterate over fuel types
 	for _, fuelEntry := range fuelList {
@@ -48,6 +54,8 @@ func (b *Bot) fuel(ctx context.Context) error {
 		if fuelEntry.IsFull {
 			slog.Debug("fuel is full", "type", fuelEntry.FuelType)
 
+			b.setFuelDecision(&fuelEntry, model.FUEL_DECISION_FULL)
+
 			continue
 		}
 
@@ -70,16 +78,16 @@ func (b *Bot) checkFuelType(ctx context.Context, fuelStruct *model.Fuel) error {
 	// select fuel tab depending on fuel type
 	switch fuelStruct.FuelType {
 	case "fuel":
-		utils.DoClickElement(ctx, model.BUTTON_COMMON_TAB1)
+		b.doClick(ctx, model.BUTTON_COMMON_TAB1)
 	case "co2":
-		utils.DoClickElement(ctx, model.BUTTON_COMMON_TAB2)
+		b.doClick(ctx, model.BUTTON_COMMON_TAB2)
 	}
 
 	// retrieve fuel information
-	if err := chromedp.Run(ctx,
-		utils.GetFloatFromElement(model.TEXT_FUEL_FUEL_PRICE, &fuelStruct.Price),
-		utils.GetFloatFromElement(model.TEXT_FUEL_FUEL_HOLDING, &fuelStruct.Holding),
-		utils.GetFloatFromElement(model.TEXT_FUEL_FUEL_CAPACITY, &fuelStruct.Capacity),
+	if err := page.ReadFloats(ctx, b.Page,
+		page.FloatField{Selector: model.TEXT_FUEL_FUEL_PRICE, Value: &fuelStruct.Price},
+		page.FloatField{Selector: model.TEXT_FUEL_FUEL_HOLDING, Value: &fuelStruct.Holding},
+		page.FloatField{Selector: model.TEXT_FUEL_FUEL_CAPACITY, Value: &fuelStruct.Capacity},
 	); err != nil {
 		slog.Warn("error in Bot.checkFuelType", "type", fuelStruct.FuelType, "error", err)
 
@@ -91,6 +99,9 @@ func (b *Bot) checkFuelType(ctx context.Context, fuelStruct *model.Fuel) error {
 	b.PrometheusMetrics.FuelHolding.WithLabelValues(fuelStruct.FuelType).Set(fuelStruct.Holding)
 	b.PrometheusMetrics.FuelLimit.WithLabelValues(fuelStruct.FuelType).Set(fuelStruct.Capacity)
 	b.PrometheusMetrics.FuelPrice.WithLabelValues(fuelStruct.FuelType).Set(fuelStruct.Price)
+	b.updateBurnRate(fuelStruct, time.Now())
+	b.setFuelStatus(*fuelStruct)
+	b.recordPrice(*fuelStruct)
 
 	// Set IsFull using helper for clarity.
 	fuelStruct.IsFull = isFuelFull(fuelStruct.Capacity, fuelStruct.Holding)
@@ -104,62 +115,180 @@ func isFuelFull(capacity, holding float64) bool {
 	return needAmount < FUEL_MINIMUM_AMOUNT
 }
 
-// buyFuelType attempts to purchase fuel of a specific type based on budget and price conditions
+// buyFuelType attempts to purchase fuel of a specific type based on budget and price conditions.
+// The tank is filled up to the level of the price tier or fully if the price tiers aren't configured.
+// The reason of the decision is stored in the Decision field.
 func (b *Bot) buyFuelType(ctx context.Context, fuelStruct *model.Fuel) error {
-	var fuelExpectedPrice float64
-
 	slog.Debug("buy fuel type", "type", fuelStruct.FuelType)
 
-	// select fuel tab depending on fuel type and set expected price
-	switch fuelStruct.FuelType {
-	case "fuel":
-		fuelExpectedPrice = b.Conf.FuelPrice.Fuel
-	case "co2":
-		fuelExpectedPrice = b.Conf.FuelPrice.Co2
+	settings := b.Conf.FuelTypes.ForType(fuelStruct.FuelType)
+	criticalPercent := b.Conf.FuelTypeCriticalPercent(fuelStruct.FuelType)
+	fuelKeepAmountPercent := (fuelStruct.Holding / fuelStruct.Capacity) * 100
+	isCritical := fuelKeepAmountPercent <= criticalPercent
+	// fill level of the tank in percents, expected price depends on fuel type, price strategy and price tiers
+	fillPercent, fuelExpectedPrice := b.fuelFillPercent(fuelStruct)
+	decision := model.FUEL_DECISION_GOOD_PRICE
+
+	if len(b.Conf.FuelTiers.ForType(fuelStruct.FuelType)) > 0 {
+		decision = model.FUEL_DECISION_PRICE_TIER
 	}
 
-	// calculate fuel need amount and price
-	fuelNeedAmount := fuelStruct.Capacity - fuelStruct.Holding
-	fuelKeepAmountPercent := (fuelStruct.Holding / fuelStruct.Capacity) * 100
-	// price per 1000 Lbs/Quotas
-	amountPrice := (fuelNeedAmount * fuelStruct.Price) / 1000
+	// the maximal price isn't exceeded even at the critical level
+	if settings.MaxPrice > 0 && fuelStruct.Price > settings.MaxPrice {
+		slog.Info("fuel is more expensive than max_price", "type", fuelStruct.FuelType, "price", int(fuelStruct.Price),
+			"max_price", int(settings.MaxPrice), "keepPercent", int(fuelKeepAmountPercent))
+
+		b.setFuelDecision(fuelStruct, model.FUEL_DECISION_MAX_PRICE)
+
+		return nil
+	}
 
 	// if fuel less than critical_percent then buy fuel anyway
-	if fuelKeepAmountPercent <= b.Conf.FuelCriticalPercent {
-		slog.Info("not enough fuel (less than fuel_critical_percent)", "type", fuelStruct.FuelType,
+	if isCritical {
+		slog.Info("not enough fuel (less than critical_percent)", "type", fuelStruct.FuelType,
 			"keepPercent", int(fuelKeepAmountPercent),
-			"critical_percent", int(b.Conf.FuelCriticalPercent))
-	} else if fuelStruct.Price > fuelExpectedPrice { // else if fuelPrice more that expectedPrice then exit
+			"critical_percent", int(criticalPercent))
+
+		fillPercent = 100
+		decision = model.FUEL_DECISION_CRITICAL
+	} else if forecastPercent := b.forecastFillPercent(fuelStruct, fuelExpectedPrice); forecastPercent > fillPercent {
+		slog.Info("fuel doesn't last until the next good price", "type", fuelStruct.FuelType, "price", int(fuelStruct.Price),
+			"burn_rate", int(fuelStruct.BurnRate), "keepPercent", int(fuelKeepAmountPercent), "fill_percent", int(forecastPercent))
+
+		fillPercent = forecastPercent
+		decision = model.FUEL_DECISION_FORECAST
+	} else if fillPercent == 0 { // else if fuelPrice more that expectedPrice then exit
 		slog.Info("fuel is too expensive", "type", fuelStruct.FuelType, "price", int(fuelStruct.Price),
 			"expected", int(fuelExpectedPrice))
 
+		b.setFuelDecision(fuelStruct, model.FUEL_DECISION_TOO_EXPENSIVE)
+
+		return nil
+	}
+
+	// calculate fuel need amount and price
+	fuelNeedAmount := fuelStruct.Capacity*fillPercent/100 - fuelStruct.Holding
+	// price per 1000 Lbs/Quotas
+	amountPrice := (fuelNeedAmount * fuelStruct.Price) / 1000
+	budget := b.fuelBudget(fuelStruct.FuelType)
+
+	if fuelNeedAmount < FUEL_MINIMUM_AMOUNT { // fuel is already above the level of the price tier
+		slog.Info("fuel is above the level of the price tier", "type", fuelStruct.FuelType, "price", int(fuelStruct.Price),
+			"keepPercent", int(fuelKeepAmountPercent), "fill_percent", fillPercent)
+
+		b.setFuelDecision(fuelStruct, model.FUEL_DECISION_ABOVE_LEVEL)
+
+		return nil
+	}
+
+	if fuelNeedAmount < settings.MinAmount { // too small purchase
+		slog.Info("fuel amount is less than min_amount", "type", fuelStruct.FuelType, "amount", int(fuelNeedAmount),
+			"min_amount", int(settings.MinAmount))
+
+		b.setFuelDecision(fuelStruct, model.FUEL_DECISION_MIN_AMOUNT)
+
 		return nil
-	} else if amountPrice > b.BudgetMoney.Fuel { // else if amountPrice more than budget then exit
+	}
+
+	if !isCritical && amountPrice > *budget { // amountPrice more than budget then exit
 		slog.Info("not enough money for buying fuel", "type", fuelStruct.FuelType, "need", int(amountPrice),
-			"budget", int(b.BudgetMoney.Fuel))
+			"budget", int(*budget))
+
+		b.setFuelDecision(fuelStruct, model.FUEL_DECISION_NO_BUDGET)
 
 		return nil
 	}
+
 	// define fuel amount string for input field
 	fuelNeedAmountString := fmt.Sprintf("%d", int(fuelNeedAmount))
 
-	slog.Debug("buying fuel", "type", fuelStruct.FuelType, "amount", fuelNeedAmountString, "price", int(amountPrice))
+	slog.Debug("buying fuel", "type", fuelStruct.FuelType, "amount", fuelNeedAmountString, "price", int(amountPrice),
+		"decision", decision)
+
+	b.setFuelDecision(fuelStruct, decision)
 
 	// perform buy fuel action
-	if err := chromedp.Run(ctx,
-		chromedp.SendKeys(model.TEXT_FIELD_FUEL_AMOUNT, fuelNeedAmountString, chromedp.ByQuery),
-		utils.ClickElement(model.BUTTON_FUEL_BUY),
-	); err != nil {
+	bought, err := b.buy(model.Purchase{
+		Item:      fuelStruct.FuelType,
+		Quantity:  fuelNeedAmount,
+		UnitPrice: fuelStruct.Price,
+		Total:     amountPrice,
+	}, func() error {
+		return runSteps(
+			func() error { return b.Page.SendKeys(ctx, model.TEXT_FIELD_FUEL_AMOUNT, fuelNeedAmountString) },
+			func() error { return b.Page.ClickWait(ctx, model.BUTTON_FUEL_BUY) },
+		)
+	})
+	if err != nil {
 		slog.Warn("error in Bot.buyFuelType", "type", fuelStruct.FuelType, "error", err)
 
 		return err
 	}
 
-	slog.Debug("money before", "AccountBalance", int(b.AccountBalance), "fuelBudget", int(b.BudgetMoney.Fuel))
+	if bought {
+		b.addBoughtFuel(fuelStruct.FuelType, fuelNeedAmount)
+	}
+
+	slog.Debug("money before", "AccountBalance", int(b.AccountBalance), "fuelBudget", int(*budget))
 	// update bot money values after purchase
 	b.AccountBalance -= amountPrice
-	b.BudgetMoney.Fuel -= amountPrice
-	slog.Debug("money after", "AccountBalance", int(b.AccountBalance), "fuelBudget", int(b.BudgetMoney.Fuel))
+	*budget -= amountPrice
+	slog.Debug("money after", "AccountBalance", int(b.AccountBalance), "fuelBudget", int(*budget))
 
 	return nil
 }
+
+// fuelBudget returns the budget of the fuel type: CO2 with its own "budget_percent" has the separate budget,
+// otherwise it shares the fuel budget.
+func (b *Bot) fuelBudget(fuelType string) *float64 {
+	if fuelType == "co2" {
+		if _, ok := b.Conf.FuelTypeBudgetPercent(fuelType); ok {
+			return &b.BudgetMoney.Co2
+		}
+	}
+
+	return &b.BudgetMoney.Fuel
+}
+
+// setFuelDecision stores the reason of the purchase decision of the fuel type in the status and the metrics.
+func (b *Bot) setFuelDecision(fuelStruct *model.Fuel, decision string) {
+	fuelStruct.Decision = decision
+
+	b.PrometheusMetrics.FuelDecision.DeletePartialMatch(prometheus.Labels{"type": fuelStruct.FuelType})
+	b.PrometheusMetrics.FuelDecision.WithLabelValues(fuelStruct.FuelType, decision).Set(1)
+	b.setFuelStatus(*fuelStruct)
+}
+
+// fuelFillPercent returns the level of the tank (in percents) to fill up to at the current price
+// and the maximal price of buying. Without the price tiers the tank is filled fully
+// if the price isn't higher than the price threshold. Zero level means that the price is too high.
+func (b *Bot) fuelFillPercent(fuelStruct *model.Fuel) (float64, float64) {
+	tiers := b.Conf.FuelTiers.ForType(fuelStruct.FuelType)
+
+	if len(tiers) == 0 {
+		threshold := b.fuelPriceThreshold(fuelStruct.FuelType)
+
+		if fuelStruct.Price > threshold {
+			return 0, threshold
+		}
+
+		return 100, threshold
+	}
+
+	var fillPercent, maxPrice float64
+
+	// the cheaper price could match several tiers, the highest level is used
+	for _, tier := range tiers {
+		maxPrice = max(maxPrice, tier.MaxPrice)
+
+		if fuelStruct.Price <= tier.MaxPrice {
+			fillPercent = max(fillPercent, tier.FillPercent)
+		}
+	}
+
+	b.PrometheusMetrics.FuelPriceThreshold.WithLabelValues(fuelStruct.FuelType).Set(maxPrice)
+
+	slog.Debug("price tier", "type", fuelStruct.FuelType, "price", fuelStruct.Price, "fill_percent", fillPercent)
+
+	return fillPercent, maxPrice
+}
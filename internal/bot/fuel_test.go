@@ -0,0 +1,276 @@
+package bot
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+	"github.com/ashokhin/am4bot/internal/prices"
+)
+
+func TestBuyFuelType(t *testing.T) {
+	testCases := map[string]struct {
+		fuel           model.Fuel
+		budget         float64
+		dryRun         bool
+		expectedBought bool
+		expectedBudget float64
+	}{
+		// critical level, buy fuel regardless of the price
+		"test01": {model.Fuel{FuelType: "fuel", Price: 2000, Holding: 10000, Capacity: 100000}, 50000, false, true, -130000},
+		// too expensive
+		"test02": {model.Fuel{FuelType: "fuel", Price: 600, Holding: 50000, Capacity: 100000}, 50000, false, false, 50000},
+		// good price, but not enough money
+		"test03": {model.Fuel{FuelType: "fuel", Price: 400, Holding: 50000, Capacity: 100000}, 10000, false, false, 10000},
+		// good price
+		"test04": {model.Fuel{FuelType: "fuel", Price: 400, Holding: 50000, Capacity: 100000}, 100000, false, true, 80000},
+		// good price for co2
+		"test05": {model.Fuel{FuelType: "co2", Price: 100, Holding: 50000, Capacity: 100000}, 100000, false, true, 95000},
+		// good price in the dry run mode
+		"test06": {model.Fuel{FuelType: "fuel", Price: 400, Holding: 50000, Capacity: 100000}, 100000, true, false, 80000},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, fakePage := newTestBot(t)
+			b.Conf.DryRun = testData.dryRun
+			b.BudgetMoney.Fuel = testData.budget
+
+			if err := b.buyFuelType(t.Context(), &testData.fuel); err != nil {
+				t.Fatalf("buyFuelType(%+v) returned error: %v", testData.fuel, err)
+			}
+
+			bought := fakePage.Clicked(model.BUTTON_FUEL_BUY) > 0
+
+			if bought != testData.expectedBought {
+				t.Errorf(`buyFuelType(%+v) bought '%t', expected '%t'`, testData.fuel, bought, testData.expectedBought)
+			}
+
+			if b.BudgetMoney.Fuel != testData.expectedBudget {
+				t.Errorf(`buyFuelType(%+v) left budget '%f', expected '%f'`, testData.fuel, b.BudgetMoney.Fuel, testData.expectedBudget)
+			}
+		})
+	}
+}
+
+func TestFuel(t *testing.T) {
+	testCases := map[string]struct {
+		fuelHolding       float64
+		co2Holding        float64
+		expectedPurchases []string
+	}{
+		"test01": {100000, 100000, nil},
+		"test02": {50000, 100000, []string{"50000"}},
+		"test03": {100000, 40000, []string{"60000"}},
+		"test04": {50000, 40000, []string{"50000", "60000"}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			var purchases []string
+
+			b, fakePage := newTestBot(t)
+			b.BudgetMoney.Fuel = 1000000
+			// every tab shows its own fuel type
+			fakePage.OnClick[model.BUTTON_COMMON_TAB1] = func(f *page.Fake) {
+				f.Texts[model.TEXT_FUEL_FUEL_PRICE] = "$ 400"
+				f.Texts[model.TEXT_FUEL_FUEL_HOLDING] = fmt.Sprintf("%.0f Lbs", testData.fuelHolding)
+				f.Texts[model.TEXT_FUEL_FUEL_CAPACITY] = "100,000 Lbs"
+			}
+			fakePage.OnClick[model.BUTTON_COMMON_TAB2] = func(f *page.Fake) {
+				f.Texts[model.TEXT_FUEL_FUEL_PRICE] = "$ 100"
+				f.Texts[model.TEXT_FUEL_FUEL_HOLDING] = fmt.Sprintf("%.0f Quotas", testData.co2Holding)
+				f.Texts[model.TEXT_FUEL_FUEL_CAPACITY] = "100,000 Quotas"
+			}
+			fakePage.OnClick[model.BUTTON_FUEL_BUY] = func(f *page.Fake) {
+				purchases = append(purchases, f.Values[model.TEXT_FIELD_FUEL_AMOUNT])
+			}
+
+			if err := b.fuel(t.Context()); err != nil {
+				t.Fatalf("fuel() returned error: %v", err)
+			}
+
+			if fmt.Sprint(purchases) != fmt.Sprint(testData.expectedPurchases) {
+				t.Errorf(`fuel() bought '%v', expected '%v'`, purchases, testData.expectedPurchases)
+			}
+		})
+	}
+}
+
+func TestFuelPriceThreshold(t *testing.T) {
+	testCases := map[string]struct {
+		strategy          string
+		observedPrices    []float64
+		minSamples        int
+		expectedThreshold float64
+	}{
+		// fixed good_price
+		"test01": {config.PRICE_STRATEGY_FIXED, []float64{300, 400, 500, 600, 700}, 0, 500},
+		// the highest price of the cheapest 40%
+		"test02": {config.PRICE_STRATEGY_PERCENTILE, []float64{700, 600, 300, 500, 400}, 5, 400},
+		// not enough observations, fallback to good_price
+		"test03": {config.PRICE_STRATEGY_PERCENTILE, []float64{700, 600, 300, 500}, 5, 500},
+		// observations older than the history days are ignored
+		"test04": {config.PRICE_STRATEGY_PERCENTILE, nil, 1, 500},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, _ := newTestBot(t)
+			b.Conf.PriceStrategy = testData.strategy
+			b.Conf.PriceHistory.Percentile = 40
+			b.Conf.PriceHistory.MinSamples = testData.minSamples
+			b.Conf.PriceHistory.File = filepath.Join(t.TempDir(), "prices.jsonl")
+			b.priceHistory = newPriceHistory(b.Conf)
+
+			now := time.Now().UTC()
+
+			if _, err := b.priceHistory.Record(prices.Observation{Timestamp: now.Add(-30 * 24 * time.Hour), Item: "fuel", Price: 100}); err != nil {
+				t.Fatalf("Record() returned error: %v", err)
+			}
+
+			for i, price := range testData.observedPrices {
+				o := prices.Observation{Timestamp: now.Add(-time.Duration(i) * prices.PRICE_PERIOD), Item: "fuel", Price: price}
+
+				if _, err := b.priceHistory.Record(o); err != nil {
+					t.Fatalf("Record(%+v) returned error: %v", o, err)
+				}
+			}
+
+			if threshold := b.fuelPriceThreshold("fuel"); threshold != testData.expectedThreshold {
+				t.Errorf(`fuelPriceThreshold() returned '%v', expected '%v'`, threshold, testData.expectedThreshold)
+			}
+		})
+	}
+}
+
+func TestBuyFuelTypeTiers(t *testing.T) {
+	tiers := []config.PriceTier{
+		{MaxPrice: 400, FillPercent: 100},
+		{MaxPrice: 900, FillPercent: 30},
+		{MaxPrice: 600, FillPercent: 60},
+	}
+
+	testCases := map[string]struct {
+		fuel           model.Fuel
+		strategy       string
+		expectedAmount string
+	}{
+		// the cheapest tier
+		"test01": {model.Fuel{FuelType: "fuel", Price: 350, Holding: 50000, Capacity: 100000}, config.PRICE_STRATEGY_FIXED, "50000"},
+		// the middle tier
+		"test02": {model.Fuel{FuelType: "fuel", Price: 600, Holding: 25000, Capacity: 100000}, config.PRICE_STRATEGY_FIXED, "35000"},
+		// the most expensive tier, the tank is already above its level
+		"test03": {model.Fuel{FuelType: "fuel", Price: 800, Holding: 50000, Capacity: 100000}, config.PRICE_STRATEGY_FIXED, ""},
+		// too expensive for all tiers
+		"test04": {model.Fuel{FuelType: "fuel", Price: 1000, Holding: 25000, Capacity: 100000}, config.PRICE_STRATEGY_FIXED, ""},
+		// critical level fills the tank fully
+		"test05": {model.Fuel{FuelType: "fuel", Price: 1000, Holding: 10000, Capacity: 100000}, config.PRICE_STRATEGY_FIXED, "90000"},
+		// co2 without tiers uses good_price
+		"test06": {model.Fuel{FuelType: "co2", Price: 110, Holding: 50000, Capacity: 100000}, config.PRICE_STRATEGY_FIXED, "50000"},
+		// tiers have priority over the percentile strategy, its threshold 200 is ignored
+		"test07": {model.Fuel{FuelType: "fuel", Price: 600, Holding: 25000, Capacity: 100000}, config.PRICE_STRATEGY_PERCENTILE, "35000"},
+		// co2 without tiers uses the percentile strategy instead of good_price
+		"test08": {model.Fuel{FuelType: "co2", Price: 150, Holding: 50000, Capacity: 100000}, config.PRICE_STRATEGY_PERCENTILE, "50000"},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, fakePage := newTestBot(t)
+			b.Conf.FuelTiers.Fuel = tiers
+			b.BudgetMoney.Fuel = 1000000
+			b.Conf.PriceStrategy = testData.strategy
+			b.Conf.PriceHistory.Percentile = 40
+			b.Conf.PriceHistory.MinSamples = 1
+			b.Conf.PriceHistory.File = filepath.Join(t.TempDir(), "prices.jsonl")
+			b.priceHistory = newPriceHistory(b.Conf)
+
+			now := time.Now().UTC()
+
+			// the percentile threshold is 200 for both types
+			for i, price := range []float64{100, 200, 300, 400, 500} {
+				for _, item := range []string{"fuel", "co2"} {
+					o := prices.Observation{Timestamp: now.Add(-time.Duration(i) * prices.PRICE_PERIOD), Item: item, Price: price}
+
+					if _, err := b.priceHistory.Record(o); err != nil {
+						t.Fatalf("Record(%+v) returned error: %v", o, err)
+					}
+				}
+			}
+
+			if err := b.buyFuelType(t.Context(), &testData.fuel); err != nil {
+				t.Fatalf("buyFuelType(%+v) returned error: %v", testData.fuel, err)
+			}
+
+			if amount := fakePage.Values[model.TEXT_FIELD_FUEL_AMOUNT]; amount != testData.expectedAmount {
+				t.Errorf(`buyFuelType(%+v) bought '%s', expected '%s'`, testData.fuel, amount, testData.expectedAmount)
+			}
+		})
+	}
+}
+
+func TestBuyFuelTypeSettings(t *testing.T) {
+	criticalPercent := 40.0
+	budgetPercent := 5.0
+
+	testCases := map[string]struct {
+		fuel             model.Fuel
+		settings         config.FuelTypeSettings
+		expectedAmount   string
+		expectedDecision string
+	}{
+		// CO2 has its own critical level
+		"test01": {model.Fuel{FuelType: "co2", Price: 200, Holding: 30000, Capacity: 100000},
+			config.FuelTypeSettings{CriticalPercent: &criticalPercent}, "70000", model.FUEL_DECISION_CRITICAL},
+		// the shared critical level
+		"test02": {model.Fuel{FuelType: "co2", Price: 200, Holding: 30000, Capacity: 100000},
+			config.FuelTypeSettings{}, "", model.FUEL_DECISION_TOO_EXPENSIVE},
+		// max_price isn't exceeded at the critical level
+		"test03": {model.Fuel{FuelType: "fuel", Price: 2000, Holding: 10000, Capacity: 100000},
+			config.FuelTypeSettings{MaxPrice: 1500}, "", model.FUEL_DECISION_MAX_PRICE},
+		// too small purchase
+		"test04": {model.Fuel{FuelType: "fuel", Price: 400, Holding: 95000, Capacity: 100000},
+			config.FuelTypeSettings{MinAmount: 10000}, "", model.FUEL_DECISION_MIN_AMOUNT},
+		// CO2 budget of its own: 5% of 100,000, the fuel budget is 70,000
+		"test05": {model.Fuel{FuelType: "co2", Price: 100, Holding: 30000, Capacity: 100000},
+			config.FuelTypeSettings{BudgetPercent: &budgetPercent}, "", model.FUEL_DECISION_NO_BUDGET},
+		"test06": {model.Fuel{FuelType: "co2", Price: 100, Holding: 60000, Capacity: 100000},
+			config.FuelTypeSettings{BudgetPercent: &budgetPercent}, "40000", model.FUEL_DECISION_GOOD_PRICE},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, fakePage := newTestBot(t)
+
+			switch testData.fuel.FuelType {
+			case "fuel":
+				b.Conf.FuelTypes.Fuel = testData.settings
+			case "co2":
+				b.Conf.FuelTypes.Co2 = testData.settings
+			}
+
+			b.AccountBalance = 100000
+			b.calcBudget()
+
+			if err := b.buyFuelType(t.Context(), &testData.fuel); err != nil {
+				t.Fatalf("buyFuelType(%+v) returned error: %v", testData.fuel, err)
+			}
+
+			if amount := fakePage.Values[model.TEXT_FIELD_FUEL_AMOUNT]; amount != testData.expectedAmount {
+				t.Errorf(`buyFuelType(%+v) bought '%s', expected '%s'`, testData.fuel, amount, testData.expectedAmount)
+			}
+
+			if testData.fuel.Decision != testData.expectedDecision {
+				t.Errorf(`buyFuelType(%+v) decided '%s', expected '%s'`, testData.fuel, testData.fuel.Decision, testData.expectedDecision)
+			}
+
+			if status := b.Status().Fuel[testData.fuel.FuelType]; status.Decision != testData.expectedDecision {
+				t.Errorf(`status decision '%s', expected '%s'`, status.Decision, testData.expectedDecision)
+			}
+		})
+	}
+}
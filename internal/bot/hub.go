@@ -6,17 +6,26 @@ import (
 	"strings"
 
 	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
 	"github.com/ashokhin/am4bot/internal/utils"
-
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/chromedp"
 )
 
+func init() {
+	RegisterService(service{name: "hubs", run: (*Bot).hubs})
+}
+
 const (
 	// HUB_WEAR_PERCENT_FOR_REPAIR defines the wear percentage threshold for lounge repair
 	HUB_WEAR_PERCENT_FOR_REPAIR float64 = 16.0
 )
 
+// hubEntry binds the hub statistics to the hub and lounge elements of the page.
+type hubEntry struct {
+	model.Hub
+	hubNode    page.Node
+	loungeNode page.Node
+}
+
 // hubs checks the status of all hubs, collects statistics, repairs lounges if needed, and buys catering.
 func (b *Bot) hubs(ctx context.Context) error {
 	var globalNeedRepair bool
@@ -25,36 +34,31 @@ func (b *Bot) hubs(ctx context.Context) error {
 	slog.Info("check hubs")
 
 	// check Alert icon for lounge on the "Flight Info" menu
-	globalNeedRepair = utils.IsElementVisible(ctx, model.ICON_FI_LOUNGE_ALERT)
+	globalNeedRepair = b.Page.Visible(ctx, model.ICON_FI_LOUNGE_ALERT, VISIBILITY_TIMEOUT)
 
 	slog.Debug("repair status", "need_repair", globalNeedRepair)
 	slog.Debug("open pop-up window", "window", "hubs")
 
 	// open hubs window
-	if err = chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_MAIN_HUBS),
-	); err != nil {
+	if err = b.Page.ClickWait(ctx, model.BUTTON_MAIN_HUBS); err != nil {
 		slog.Warn("error in Bot.hubs > open hubs", "error", err)
 
 		return err
 	}
 
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 
 	slog.Debug("get list of hubs webElements")
 
-	var hubsElemList []*cdp.Node
-
 	// get list of Hubs
-	if err = chromedp.Run(ctx,
-		chromedp.Nodes(model.LIST_HUBS_HUBS, &hubsElemList, chromedp.ByQueryAll),
-	); err != nil {
+	hubsElemList, err := b.Page.Nodes(ctx, model.LIST_HUBS_HUBS)
+	if err != nil {
 		slog.Warn("error in Bot.hubs > get hubs list", "error", err)
 
 		return err
 	}
 
-	var hubsMap map[string]model.Hub
+	var hubsMap map[string]hubEntry
 	// collect metrics for all hubs
 	if hubsMap, err = b.hubsCollectMetrics(ctx, hubsElemList); err != nil {
 		slog.Warn("error in Bot.hubs > Bot.hubsCollectMetrics", "error", err)
@@ -86,37 +90,45 @@ func (b *Bot) hubs(ctx context.Context) error {
 }
 
 // hubsCollectMetrics collects Prometheus metrics for all available hubs
-func (b *Bot) hubsCollectMetrics(ctx context.Context, hubsElemList []*cdp.Node) (map[string]model.Hub, error) {
-	hubsMap := make(map[string]model.Hub)
+func (b *Bot) hubsCollectMetrics(ctx context.Context, hubsElemList []page.Node) (map[string]hubEntry, error) {
+	hubsMap := make(map[string]hubEntry)
 	// get metrics for all hubs in hubsElemList
 	for _, hubElem := range hubsElemList {
-		var hub model.Hub
-		var hubName string
+		var hub hubEntry
 
-		hub.HubCdpNode = hubElem
+		hub.hubNode = hubElem
 
-		slog.Debug("hubElem", "elem", hub.HubCdpNode)
+		slog.Debug("hubElem", "elem", hub.hubNode)
+
+		hubName, err := hub.hubNode.Text(ctx, model.TEXT_HUBS_HUB_NAME)
+		if err != nil {
+			slog.Warn("error in Bot.hubsCollectMetrics > get hub name", "error", err)
+
+			return nil, err
+		}
 
 		// retrieve hub statistics
-		if err := chromedp.Run(ctx,
-			chromedp.Text(model.TEXT_HUBS_HUB_NAME, &hubName, chromedp.ByQuery, chromedp.FromNode(hub.HubCdpNode)),
-			utils.GetFloatFromChildElement(model.TEXT_HUBS_HUB_DEPARTURES, &hub.Departures, hub.HubCdpNode),
-			utils.GetFloatFromChildElement(model.TEXT_HUBS_HUB_ARRIVALS, &hub.Arrivals, hub.HubCdpNode),
-			utils.GetFloatFromChildElement(model.TEXT_HUBS_HUB_PAX_DEPARTED, &hub.PaxDeparted, hub.HubCdpNode),
-			utils.GetFloatFromChildElement(model.TEXT_HUBS_HUB_PAX_ARRIVED, &hub.PaxArrived, hub.HubCdpNode),
+		if err := page.ReadChildFloats(ctx, hub.hubNode,
+			page.FloatField{Selector: model.TEXT_HUBS_HUB_DEPARTURES, Value: &hub.Departures},
+			page.FloatField{Selector: model.TEXT_HUBS_HUB_ARRIVALS, Value: &hub.Arrivals},
+			page.FloatField{Selector: model.TEXT_HUBS_HUB_PAX_DEPARTED, Value: &hub.PaxDeparted},
+			page.FloatField{Selector: model.TEXT_HUBS_HUB_PAX_ARRIVED, Value: &hub.PaxArrived},
 		); err != nil {
 			slog.Warn("error in Bot.hubsCollectMetrics > get hub info", "error", err)
 
 			return nil, err
 		}
 
+		hub.Name = hubName
+
 		// check if catering is present
-		hub.HasCatering = utils.IsSubElementVisible(ctx, model.ICON_HUBS_CATERING, hub.HubCdpNode)
+		hub.HasCatering = hub.hubNode.Visible(ctx, model.ICON_HUBS_CATERING)
 
 		b.PrometheusMetrics.HubStatsTotal.WithLabelValues(hubName, "departures").Set(hub.Departures)
 		b.PrometheusMetrics.HubStatsTotal.WithLabelValues(hubName, "arrivals").Set(hub.Arrivals)
 		b.PrometheusMetrics.HubStatsTotal.WithLabelValues(hubName, "paxDeparted").Set(hub.PaxDeparted)
 		b.PrometheusMetrics.HubStatsTotal.WithLabelValues(hubName, "paxArrived").Set(hub.PaxArrived)
+		b.setHubStatus(hub.Hub)
 
 		hubsMap[hubName] = hub
 	}
@@ -126,20 +138,18 @@ func (b *Bot) hubsCollectMetrics(ctx context.Context, hubsElemList []*cdp.Node)
 
 // hubsLoungesRepair performs repair operation for limited number of hubs. Limit comes from the
 // configuration option "bot.Conf.hubs_maintenance_limit"
-func (b *Bot) hubsLoungesRepair(ctx context.Context, hubsMap map[string]model.Hub) error {
+func (b *Bot) hubsLoungesRepair(ctx context.Context, hubsMap map[string]hubEntry) error {
 	var err error
 	loungesRepairCount := 0
 
 	// open lounges maintenance tab
-	if err = chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_HUBS_LOUNGES_MAINTENANCE),
-	); err != nil {
+	if err = b.Page.ClickWait(ctx, model.BUTTON_HUBS_LOUNGES_MAINTENANCE); err != nil {
 		slog.Warn("error in Bot.hubsLoungesRepair > open lounges maintenance tab", "error", err)
 
 		return err
 	}
 
-	defer utils.DoClickElement(ctx, model.BUTTON_HUBS_LOUNGES_BACK_TO_HUBS)
+	defer b.doClick(ctx, model.BUTTON_HUBS_LOUNGES_BACK_TO_HUBS)
 
 	// perform repair for the first N ( defined by the config option "bot.Conf.hubs_maintenance_limit")
 	// hubs number in hubsMap
@@ -184,13 +194,10 @@ func (b *Bot) hubsLoungesRepair(ctx context.Context, hubsMap map[string]model.Hu
 }
 
 // collectLoungeInfo collects information about hub's lounge and overrides the hub object by reference
-func (b *Bot) collectLoungeInfo(ctx context.Context, hubName string, hub *model.Hub) error {
-	var err error
-	var loungesElemList []*cdp.Node
+func (b *Bot) collectLoungeInfo(ctx context.Context, hubName string, hub *hubEntry) error {
 	// open lounges tab and get list of lounges
-	if err = chromedp.Run(ctx,
-		chromedp.Nodes(model.LIST_HUBS_LOUNGES, &loungesElemList, chromedp.ByQueryAll),
-	); err != nil {
+	loungesElemList, err := b.Page.Nodes(ctx, model.LIST_HUBS_LOUNGES)
+	if err != nil {
 		slog.Warn("error in Bot.collectLoungeInfo > get lounges list", "error", err)
 
 		return err
@@ -203,9 +210,17 @@ func (b *Bot) collectLoungeInfo(ctx context.Context, hubName string, hub *model.
 		var needsRepair bool
 
 		// retrieve lounge statistics
-		if err := chromedp.Run(ctx,
-			chromedp.Text(model.TEXT_HUBS_LOUNGES_LOUNGE_NAME, &loungeName, chromedp.ByQuery, chromedp.FromNode(loungeElem)),
-			utils.GetFloatFromChildElement(model.TEXT_HUBS_LOUNGES_LOUNGE_WEAR_PERCENT, &loungeWearPercent, loungeElem),
+		if err := runSteps(
+			func() (err error) {
+				loungeName, err = loungeElem.Text(ctx, model.TEXT_HUBS_LOUNGES_LOUNGE_NAME)
+
+				return err
+			},
+			func() (err error) {
+				loungeWearPercent, err = loungeElem.Float(ctx, model.TEXT_HUBS_LOUNGES_LOUNGE_WEAR_PERCENT)
+
+				return err
+			},
 		); err != nil {
 			slog.Warn("error in Bot.collectLoungeInfo > get lounge info", "error", err)
 
@@ -230,7 +245,7 @@ func (b *Bot) collectLoungeInfo(ctx context.Context, hubName string, hub *model.
 		// set lounge wear percent and repair status into hubsMap
 		if strings.Contains(hubName, loungeName) {
 			hub.NeedsRepair = needsRepair
-			hub.LoungeCdpNode = loungeElem
+			hub.loungeNode = loungeElem
 
 			slog.Debug("updated hub info in hubsMap", "hub_name", hubName, "hub_info", hub)
 
@@ -243,7 +258,7 @@ func (b *Bot) collectLoungeInfo(ctx context.Context, hubName string, hub *model.
 
 // hubsBuyCatering buys catering for limited number of hubs. Limit comes from the
 // configuration option "bot.Conf.hubs_maintenance_limit"
-func (b *Bot) hubsBuyCatering(ctx context.Context, hubsMap map[string]model.Hub) error {
+func (b *Bot) hubsBuyCatering(ctx context.Context, hubsMap map[string]hubEntry) error {
 	hubsBuyCateringCount := 0
 	// perform catering buy for the first N ( defined by the config option "bot.Conf.hubs_maintenance_limit")
 	// hubs number in hubsMap
@@ -272,16 +287,15 @@ func (b *Bot) hubsBuyCatering(ctx context.Context, hubsMap map[string]model.Hub)
 }
 
 // repairLounge repairs the lounge of a specific hub if the repair cost is within the maintenance budget.
-func (b *Bot) repairLounge(ctx context.Context, hub *model.Hub) error {
+func (b *Bot) repairLounge(ctx context.Context, hub *hubEntry) error {
 	slog.Debug("repair lounge function")
 
 	var loungeRepairCost float64
+	var err error
 
-	if utils.IsSubElementVisible(ctx, model.TEXT_HUBS_LOUNGES_LOUNGE_REPAIR_COST, hub.LoungeCdpNode) {
+	if hub.loungeNode.Visible(ctx, model.TEXT_HUBS_LOUNGES_LOUNGE_REPAIR_COST) {
 		// get repair cost
-		if err := chromedp.Run(ctx,
-			utils.GetFloatFromChildElement(model.TEXT_HUBS_LOUNGES_LOUNGE_REPAIR_COST, &loungeRepairCost, hub.LoungeCdpNode),
-		); err != nil {
+		if loungeRepairCost, err = hub.loungeNode.Float(ctx, model.TEXT_HUBS_LOUNGES_LOUNGE_REPAIR_COST); err != nil {
 			slog.Warn("error in Bot.repairLounge > get lounge repair cost", "error", err)
 
 			return err
@@ -306,9 +320,15 @@ func (b *Bot) repairLounge(ctx context.Context, hub *model.Hub) error {
 	slog.Info("repair lounge", "repairCost", int(loungeRepairCost),
 		"BudgetMoney.Maintenance", int(b.BudgetMoney.Maintenance))
 
-	if err := chromedp.Run(ctx,
-		chromedp.Click(model.BUTTON_HUBS_LOUNGES_LOUNGE_REPAIR, chromedp.ByQuery, chromedp.FromNode(hub.LoungeCdpNode)),
-	); err != nil {
+	bought, err := b.buy(model.Purchase{
+		Item:     "lounge",
+		Details:  hub.Name,
+		Quantity: 1,
+		Total:    loungeRepairCost,
+	}, func() error {
+		return hub.loungeNode.Click(ctx, model.BUTTON_HUBS_LOUNGES_LOUNGE_REPAIR)
+	})
+	if err != nil {
 		slog.Warn("error in Bot.repairLounge > click repair", "error", err)
 
 		return err
@@ -318,11 +338,16 @@ func (b *Bot) repairLounge(ctx context.Context, hub *model.Hub) error {
 	b.AccountBalance -= loungeRepairCost
 	b.BudgetMoney.Maintenance -= loungeRepairCost
 
+	// lounges grid isn't changed if the repair has been skipped
+	if !bought {
+		return nil
+	}
+
 	// after clicking the "repair" button,
 	// lounges grid is redrawn, so we need to re-open lounges maintenance tab
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_HUBS_LOUNGES_BACK_TO_HUBS),
-		utils.ClickElement(model.BUTTON_HUBS_LOUNGES_MAINTENANCE),
+	if err := b.clickAll(ctx,
+		model.BUTTON_HUBS_LOUNGES_BACK_TO_HUBS,
+		model.BUTTON_HUBS_LOUNGES_MAINTENANCE,
 	); err != nil {
 		slog.Warn("error in Bot.repairLounge > reopen lounges tab", "error", err)
 
@@ -333,21 +358,19 @@ func (b *Bot) repairLounge(ctx context.Context, hub *model.Hub) error {
 }
 
 // buyCatering buys catering for a specific hub if the catering cost is within the maintenance budget.
-func (b *Bot) buyCatering(ctx context.Context, hub model.Hub) error {
+func (b *Bot) buyCatering(ctx context.Context, hub hubEntry) error {
 	slog.Debug("Buy catering function")
 
-	if err := chromedp.Run(ctx,
-		chromedp.Click(model.ELEMENT_HUB, chromedp.ByQuery, chromedp.FromNode(hub.HubCdpNode)),
-	); err != nil {
+	if err := hub.hubNode.Click(ctx, model.ELEMENT_HUB); err != nil {
 		slog.Warn("error in Bot.buyCatering > select hub", "error", err)
 
 		return err
 	}
 
 	// return to list of hubs when exiting from function
-	defer utils.DoClickElement(ctx, model.BUTTON_HUBS_HUB_MANAGE_BACK)
+	defer b.doClick(ctx, model.BUTTON_HUBS_HUB_MANAGE_BACK)
 
-	if !utils.IsElementVisible(ctx, model.BUTTON_HUBS_ADD_CATERING) {
+	if !b.Page.Visible(ctx, model.BUTTON_HUBS_ADD_CATERING, VISIBILITY_TIMEOUT) {
 		slog.Warn("button '+ Add catering' isn't visible")
 
 		return nil
@@ -355,13 +378,21 @@ func (b *Bot) buyCatering(ctx context.Context, hub model.Hub) error {
 
 	var cateringCost float64
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_HUBS_ADD_CATERING),
-		chromedp.WaitReady(model.ELEM_HUBS_CATERING_OPTION_3, chromedp.ByQuery),
-		utils.ClickElement(model.ELEM_HUBS_CATERING_OPTION_3),
-		chromedp.SetValue(model.SELECT_HUBS_CATERING_DURATION, b.Conf.CateringDurationHours, chromedp.ByQuery),
-		chromedp.SetValue(model.SELECT_HUBS_CATERING_AMOUNT, b.Conf.CateringAmountOption, chromedp.ByQuery),
-		utils.GetFloatFromElement(model.TEXT_HUBS_CATERING_COST, &cateringCost),
+	if err := runSteps(
+		func() error { return b.Page.ClickWait(ctx, model.BUTTON_HUBS_ADD_CATERING) },
+		func() error { return b.Page.WaitReady(ctx, model.ELEM_HUBS_CATERING_OPTION_3) },
+		func() error { return b.Page.ClickWait(ctx, model.ELEM_HUBS_CATERING_OPTION_3) },
+		func() error {
+			return b.Page.SetValue(ctx, model.SELECT_HUBS_CATERING_DURATION, b.Conf.CateringDurationHours)
+		},
+		func() error {
+			return b.Page.SetValue(ctx, model.SELECT_HUBS_CATERING_AMOUNT, b.Conf.CateringAmountOption)
+		},
+		func() (err error) {
+			cateringCost, err = b.Page.Float(ctx, model.TEXT_HUBS_CATERING_COST)
+
+			return err
+		},
 	); err != nil {
 		slog.Warn("error in Bot.buyCatering > select hub", "error", err)
 
@@ -375,10 +406,17 @@ func (b *Bot) buyCatering(ctx context.Context, hub model.Hub) error {
 		return nil
 	}
 
+	cateringAmount := float64(utils.AtoiSafe(b.Conf.CateringAmountOption))
+
 	// buy catering
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_HUBS_CATERING_BUY),
-	); err != nil {
+	if _, err := b.buy(model.Purchase{
+		Item:     "catering",
+		Details:  hub.Name,
+		Quantity: cateringAmount,
+		Total:    cateringCost,
+	}, func() error {
+		return b.Page.ClickWait(ctx, model.BUTTON_HUBS_CATERING_BUY)
+	}); err != nil {
 		slog.Warn("error in Bot.buyCatering > buy catering", "error", err)
 
 		return err
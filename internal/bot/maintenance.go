@@ -2,25 +2,28 @@ package bot
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sort"
 	"strings"
 
 	"github.com/ashokhin/am4bot/internal/model"
-	"github.com/ashokhin/am4bot/internal/utils"
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/chromedp"
+	"github.com/ashokhin/am4bot/internal/page"
 )
 
+func init() {
+	RegisterService(service{name: "ac_maintenance", run: (*Bot).maintenance})
+}
+
 // maintenance performs maintenance operations on aircraft, including A-Checks, repairs, and modifications.
 func (b *Bot) maintenance(ctx context.Context) error {
 	slog.Info("aircraft maintenance")
 
 	slog.Debug("open pop-up window", "window", "maintenance")
 	// open the "Maintenance" pop-up
-	utils.DoClickElement(ctx, model.BUTTON_MAIN_MAINTENANCE)
+	b.doClick(ctx, model.BUTTON_MAIN_MAINTENANCE)
 
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 
 	// perform the 'A-Check' operation on all eligible aircraft
 	if err := b.aCheckAllAircraft(ctx); err != nil {
@@ -49,18 +52,22 @@ func (b *Bot) maintenance(ctx context.Context) error {
 // aCheckAllAircraft performs A-Check maintenance on all eligible aircraft.
 func (b *Bot) aCheckAllAircraft(ctx context.Context) error {
 	var aircraftNeedACheck int
-	var aircraftElemList []*cdp.Node
+	var aircraftElemList []page.Node
 
 	slog.Info("search aircraft which need A-Check")
 	slog.Debug("get list of aircraftElements")
 
-	if err := chromedp.Run(ctx,
-		// open "Plan +" tab
-		utils.ClickElement(model.BUTTON_COMMON_TAB2),
-		// click on "Bulk A-Check" button
-		utils.ClickElement(model.BUTTON_MAINTENANCE_BULK_ACHECK),
+	if err := runSteps(
+		// open "Plan +" tab and click on "Bulk A-Check" button
+		func() error {
+			return b.clickAll(ctx, model.BUTTON_COMMON_TAB2, model.BUTTON_MAINTENANCE_BULK_ACHECK)
+		},
 		// search all "aircraft" rows
-		chromedp.Nodes(model.LIST_MAINTENANCE_BULK_ACHECK_AC_LIST, &aircraftElemList, chromedp.ByQueryAll),
+		func() (err error) {
+			aircraftElemList, err = b.Page.Nodes(ctx, model.LIST_MAINTENANCE_BULK_ACHECK_AC_LIST)
+
+			return err
+		},
 	); err != nil {
 		slog.Warn("error in Bot.aCheckAllAircraft > get aircraftElements list", "error", err)
 
@@ -69,12 +76,9 @@ func (b *Bot) aCheckAllAircraft(ctx context.Context) error {
 
 	// Select all eligible aircraft for A-Check maintenance and count total A-Check cost
 	for _, aircraftElem := range aircraftElemList {
-		var acACheckHours int
-
-		if err := chromedp.Run(ctx,
-			utils.GetIntFromChildElement(model.TEXT_MAINTENANCE_BULK_ACHECK_HOURS, &acACheckHours, aircraftElem),
-		); err != nil {
-			slog.Warn("error in Bot.aCheckAllAircraft > utils.GetIntFromChildElement", "error", err)
+		acACheckHours, err := aircraftElem.Int(ctx, model.TEXT_MAINTENANCE_BULK_ACHECK_HOURS)
+		if err != nil {
+			slog.Warn("error in Bot.aCheckAllAircraft > get a-check hours", "error", err)
 
 			continue
 		}
@@ -87,9 +91,7 @@ func (b *Bot) aCheckAllAircraft(ctx context.Context) error {
 
 		slog.Debug("add aircraft for a-check", "a-check hours", acACheckHours)
 
-		if err := chromedp.Run(ctx,
-			chromedp.Click(model.TEXT_MAINTENANCE_BULK_ACHECK_HOURS, chromedp.ByQuery, chromedp.FromNode(aircraftElem)),
-		); err != nil {
+		if err := aircraftElem.Click(ctx, model.TEXT_MAINTENANCE_BULK_ACHECK_HOURS); err != nil {
 			slog.Warn("error in Bot.aCheckAllAircraft > click 'Plan' button for aircraft", "error", err)
 
 			continue
@@ -105,11 +107,8 @@ func (b *Bot) aCheckAllAircraft(ctx context.Context) error {
 	}
 
 	// Get total A-Check cost for all selected aircraft
-	var totalACheckCost float64
-
-	if err := chromedp.Run(ctx,
-		utils.GetFloatFromElement(model.TEXT_MAINTENANCE_BULK_ACHECK_COST, &totalACheckCost),
-	); err != nil {
+	totalACheckCost, err := b.Page.Float(ctx, model.TEXT_MAINTENANCE_BULK_ACHECK_COST)
+	if err != nil {
 		slog.Warn("error in Bot.aCheckAllAircraft > get total A-Check cost", "error", err)
 
 		return err
@@ -127,9 +126,13 @@ func (b *Bot) aCheckAllAircraft(ctx context.Context) error {
 	slog.Info("plan A-Check maintenance for selected aircraft", "count", aircraftNeedACheck, "totalCost", int(totalACheckCost))
 
 	// Click the "Plan bulk check" button to schedule A-Check maintenance for all selected aircraft
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_MAINTENANCE_BULK_ACHECK_PLAN),
-	); err != nil {
+	if _, err := b.buy(model.Purchase{
+		Item:     "a-check",
+		Quantity: float64(aircraftNeedACheck),
+		Total:    totalACheckCost,
+	}, func() error {
+		return b.Page.ClickWait(ctx, model.BUTTON_MAINTENANCE_BULK_ACHECK_PLAN)
+	}); err != nil {
 		slog.Warn("error in Bot.aCheckAllAircraft > plan A-Check maintenance for selected aircraft", "error", err)
 
 		return err
@@ -147,13 +150,15 @@ func (b *Bot) repairAllAircraft(ctx context.Context) error {
 	slog.Info("search aircraft which need repair")
 	slog.Debug("get list of aircraftElements")
 
-	if err := chromedp.Run(ctx,
-		// open "Plan +" tab
-		utils.ClickElement(model.BUTTON_COMMON_TAB2),
-		// open "Bulk Repair" menu
-		utils.ClickElement(model.BUTTON_MAINTENANCE_BULK_REPAIR),
+	if err := runSteps(
+		// open "Plan +" tab and "Bulk Repair" menu
+		func() error {
+			return b.clickAll(ctx, model.BUTTON_COMMON_TAB2, model.BUTTON_MAINTENANCE_BULK_REPAIR)
+		},
 		// set "Repair %" filter
-		chromedp.SetValue(model.SELECT_MAINTENANCE_BULK_REPAIR_PERCENT, b.Conf.AircraftWearPercent, chromedp.ByQuery),
+		func() error {
+			return b.Page.SetValue(ctx, model.SELECT_MAINTENANCE_BULK_REPAIR_PERCENT, b.Conf.AircraftWearPercent)
+		},
 	); err != nil {
 		slog.Warn("error in Bot.repairAllAircraft > set repair value filter", "error", err)
 
@@ -161,17 +166,14 @@ func (b *Bot) repairAllAircraft(ctx context.Context) error {
 	}
 
 	// Check if repair cost is visible after setting the filter, if not - then no aircraft need repair
-	if !utils.IsElementVisible(ctx, model.TEXT_MAINTENANCE_BULK_REPAIR_COST) {
+	if !b.Page.Visible(ctx, model.TEXT_MAINTENANCE_BULK_REPAIR_COST, VISIBILITY_TIMEOUT) {
 		slog.Info("no aircraft need repair")
 
 		return nil
 	}
 
-	var totalRepairCost float64
-
-	if err := chromedp.Run(ctx,
-		utils.GetFloatFromElement(model.TEXT_MAINTENANCE_BULK_REPAIR_COST, &totalRepairCost),
-	); err != nil {
+	totalRepairCost, err := b.Page.Float(ctx, model.TEXT_MAINTENANCE_BULK_REPAIR_COST)
+	if err != nil {
 		slog.Warn("error in Bot.repairAllAircraft > get total repair cost", "error", err)
 
 		return err
@@ -188,9 +190,14 @@ func (b *Bot) repairAllAircraft(ctx context.Context) error {
 
 	slog.Info("plan repair maintenance for selected aircraft", "totalCost", int(totalRepairCost))
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_MAINTENANCE_BULK_REPAIR_PLAN),
-	); err != nil {
+	if _, err := b.buy(model.Purchase{
+		Item:     "repair",
+		Details:  fmt.Sprintf("wear >= %s%%", b.Conf.AircraftWearPercent),
+		Quantity: 1,
+		Total:    totalRepairCost,
+	}, func() error {
+		return b.Page.ClickWait(ctx, model.BUTTON_MAINTENANCE_BULK_REPAIR_PLAN)
+	}); err != nil {
 		slog.Warn("error in Bot.repairAllAircraft > plan repair maintenance for selected aircraft", "error", err)
 
 		return err
@@ -207,18 +214,22 @@ func (b *Bot) repairAllAircraft(ctx context.Context) error {
 func (b *Bot) modifyAllAircraft(ctx context.Context) error {
 	var aircraftPlaned int
 	var aircraftNeedModify []model.Aircraft
-	var aircraftElemList []*cdp.Node
+	var aircraftElemList []page.Node
 
 	slog.Info("search aircraft which need modify")
 	slog.Debug("get list of aircraftElements")
 
-	if err := chromedp.Run(ctx,
-		// open "Plan +" tab
-		utils.ClickElement(model.BUTTON_COMMON_TAB2),
-		// click on "Base only" button
-		utils.ClickElement(model.BUTTON_MAINTENANCE_BASE_ONLY),
+	if err := runSteps(
+		// open "Plan +" tab and click on "Base only" button
+		func() error {
+			return b.clickAll(ctx, model.BUTTON_COMMON_TAB2, model.BUTTON_MAINTENANCE_BASE_ONLY)
+		},
 		// search all "aircraft" rows
-		chromedp.Nodes(model.LIST_MAINTENANCE_AC_LIST, &aircraftElemList, chromedp.ByQueryAll),
+		func() (err error) {
+			aircraftElemList, err = b.Page.Nodes(ctx, model.LIST_MAINTENANCE_AC_LIST)
+
+			return err
+		},
 	); err != nil {
 		slog.Warn("error in Bot.modifyAllAircraft > get aircraftElements list", "error", err)
 
@@ -233,8 +244,8 @@ func (b *Bot) modifyAllAircraft(ctx context.Context) error {
 
 		var aircraft model.Aircraft
 
-		aircraft.RegNumber = aircraftElem.AttributeValue(model.TEXT_MAINTENANCE_AC_REG_NUMBER)
-		aircraft.AcType = aircraftElem.AttributeValue(model.TEXT_MAINTENANCE_AC_TYPE)
+		aircraft.RegNumber = aircraftElem.Attribute(model.TEXT_MAINTENANCE_AC_REG_NUMBER)
+		aircraft.AcType = aircraftElem.Attribute(model.TEXT_MAINTENANCE_AC_TYPE)
 
 		slog.Debug("add aircraft for modify check", "aircraft", aircraft.RegNumber)
 
@@ -279,17 +290,20 @@ func (b *Bot) modifyAllAircraft(ctx context.Context) error {
 
 // modifyAc performs a specific maintenance operation (A-Check, Repair, Modify) on a given aircraft.
 func (b *Bot) modifyAc(ctx context.Context, ac model.Aircraft) (bool, error) {
-	var mntOperationCost float64
-	var acWebElemNode *cdp.Node
+	var acWebElemNode page.Node
 
 	slog.Debug("modify aircraft", "reg.number", strings.ToUpper(ac.RegNumber))
 	slog.Debug("get aircraft rows")
 
-	var aircraftElemList []*cdp.Node
+	var aircraftElemList []page.Node
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_COMMON_TAB2),
-		chromedp.Nodes(model.LIST_MAINTENANCE_AC_LIST, &aircraftElemList, chromedp.ByQueryAll),
+	if err := runSteps(
+		func() error { return b.Page.ClickWait(ctx, model.BUTTON_COMMON_TAB2) },
+		func() (err error) {
+			aircraftElemList, err = b.Page.Nodes(ctx, model.LIST_MAINTENANCE_AC_LIST)
+
+			return err
+		},
 	); err != nil {
 		slog.Warn("error in Bot.modifyAc > get aircraftElements list", "error", err)
 
@@ -299,7 +313,7 @@ func (b *Bot) modifyAc(ctx context.Context, ac model.Aircraft) (bool, error) {
 	slog.Debug("search aircraft row")
 
 	for _, acElem := range aircraftElemList {
-		if ac.RegNumber == acElem.AttributeValue(model.TEXT_MAINTENANCE_AC_REG_NUMBER) {
+		if ac.RegNumber == acElem.Attribute(model.TEXT_MAINTENANCE_AC_REG_NUMBER) {
 			slog.Debug("row found")
 
 			acWebElemNode = acElem
@@ -317,19 +331,17 @@ func (b *Bot) modifyAc(ctx context.Context, ac model.Aircraft) (bool, error) {
 	slog.Debug("get cost for aircraft modification", "reg.number", strings.ToUpper(ac.RegNumber))
 
 	// open modification window
-	if err := chromedp.Run(ctx,
-		chromedp.Click(model.BUTTON_MAINTENANCE_MODIFY, chromedp.ByQuery, chromedp.FromNode(acWebElemNode)),
-	); err != nil {
+	if err := acWebElemNode.Click(ctx, model.BUTTON_MAINTENANCE_MODIFY); err != nil {
 		slog.Warn("error in Bot.modifyAc > open modification window", "error", err)
 
 		return false, err
 	}
 
 	// select all available modification options
-	if err := chromedp.Run(ctx,
-		chromedp.Click(model.CHECKBOX_MAINTENANCE_MODIFY_MOD1, chromedp.ByQuery),
-		chromedp.Click(model.CHECKBOX_MAINTENANCE_MODIFY_MOD2, chromedp.ByQuery),
-		chromedp.Click(model.CHECKBOX_MAINTENANCE_MODIFY_MOD3, chromedp.ByQuery),
+	if err := runSteps(
+		func() error { return b.Page.Click(ctx, model.CHECKBOX_MAINTENANCE_MODIFY_MOD1) },
+		func() error { return b.Page.Click(ctx, model.CHECKBOX_MAINTENANCE_MODIFY_MOD2) },
+		func() error { return b.Page.Click(ctx, model.CHECKBOX_MAINTENANCE_MODIFY_MOD3) },
 	); err != nil {
 		slog.Warn("error in Bot.modifyAc > flag 'modify' options", "error", err)
 
@@ -337,9 +349,8 @@ func (b *Bot) modifyAc(ctx context.Context, ac model.Aircraft) (bool, error) {
 	}
 
 	// get final cost for maintenance operation
-	if err := chromedp.Run(ctx,
-		utils.GetFloatFromElement(model.TEXT_MAINTENANCE_MODIFY_TOTAL_COST, &mntOperationCost),
-	); err != nil {
+	mntOperationCost, err := b.Page.Float(ctx, model.TEXT_MAINTENANCE_MODIFY_TOTAL_COST)
+	if err != nil {
 		slog.Warn("error in Bot.modifyAc > get operation cost", "error", err)
 
 		return false, err
@@ -363,9 +374,15 @@ func (b *Bot) modifyAc(ctx context.Context, ac model.Aircraft) (bool, error) {
 
 	slog.Info("plan modification", "reg.number", strings.ToUpper(ac.RegNumber))
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_MAINTENANCE_PLAN_MODIFY),
-	); err != nil {
+	bought, err := b.buy(model.Purchase{
+		Item:     "modify",
+		Details:  strings.ToUpper(ac.RegNumber),
+		Quantity: 1,
+		Total:    mntOperationCost,
+	}, func() error {
+		return b.Page.ClickWait(ctx, model.BUTTON_MAINTENANCE_PLAN_MODIFY)
+	})
+	if err != nil {
 		slog.Warn("error in Bot.modifyAc > plan modification operation", "error", err)
 
 		return false, err
@@ -375,5 +392,5 @@ func (b *Bot) modifyAc(ctx context.Context, ac model.Aircraft) (bool, error) {
 	b.BudgetMoney.Maintenance -= mntOperationCost
 	b.AccountBalance -= mntOperationCost
 
-	return true, nil
+	return bought, nil
 }
@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+)
+
+func TestACheckAllAircraft(t *testing.T) {
+	testCases := map[string]struct {
+		aCheckHours     []int
+		budget          float64
+		expectedChecked int
+		expectedPlanned bool
+	}{
+		"test01": {[]int{10, 30, 24}, 100000, 2, true},
+		"test02": {[]int{10, 30, 24}, 10000, 2, false},
+		"test03": {[]int{30, 100}, 100000, 0, false},
+		"test04": {nil, 100000, 0, false},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			var aircraftNodes []*page.FakeNode
+
+			b, fakePage := newTestBot(t)
+			b.BudgetMoney.Maintenance = testData.budget
+			fakePage.Texts[model.TEXT_MAINTENANCE_BULK_ACHECK_COST] = "$ 50,000"
+
+			for _, hours := range testData.aCheckHours {
+				aircraftNodes = append(aircraftNodes, &page.FakeNode{
+					Texts: map[string]string{model.TEXT_MAINTENANCE_BULK_ACHECK_HOURS: strconv.Itoa(hours)},
+				})
+			}
+
+			fakePage.Lists[model.LIST_MAINTENANCE_BULK_ACHECK_AC_LIST] = aircraftNodes
+
+			if err := b.aCheckAllAircraft(t.Context()); err != nil {
+				t.Fatalf("aCheckAllAircraft() returned error: %v", err)
+			}
+
+			checked := 0
+
+			for _, n := range aircraftNodes {
+				checked += len(n.Clicks)
+			}
+
+			if checked != testData.expectedChecked {
+				t.Errorf(`aCheckAllAircraft() selected '%d' aircraft, expected '%d'`, checked, testData.expectedChecked)
+			}
+
+			planned := fakePage.Clicked(model.BUTTON_MAINTENANCE_BULK_ACHECK_PLAN) > 0
+
+			if planned != testData.expectedPlanned {
+				t.Errorf(`aCheckAllAircraft() planned '%t', expected '%t'`, planned, testData.expectedPlanned)
+			}
+		})
+	}
+}
+
+func TestRepairAllAircraft(t *testing.T) {
+	testCases := map[string]struct {
+		repairCost      string
+		budget          float64
+		expectedPlanned bool
+	}{
+		"test01": {"$ 20,000", 100000, true},
+		"test02": {"$ 200,000", 100000, false},
+		// no aircraft need repair
+		"test03": {"", 100000, false},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, fakePage := newTestBot(t)
+			b.BudgetMoney.Maintenance = testData.budget
+
+			if testData.repairCost != "" {
+				fakePage.Texts[model.TEXT_MAINTENANCE_BULK_REPAIR_COST] = testData.repairCost
+			}
+
+			if err := b.repairAllAircraft(t.Context()); err != nil {
+				t.Fatalf("repairAllAircraft() returned error: %v", err)
+			}
+
+			if value := fakePage.Values[model.SELECT_MAINTENANCE_BULK_REPAIR_PERCENT]; value != b.Conf.AircraftWearPercent {
+				t.Errorf(`repairAllAircraft() set wear filter '%s', expected '%s'`, value, b.Conf.AircraftWearPercent)
+			}
+
+			planned := fakePage.Clicked(model.BUTTON_MAINTENANCE_BULK_REPAIR_PLAN) > 0
+
+			if planned != testData.expectedPlanned {
+				t.Errorf(`repairAllAircraft() planned '%t', expected '%t'`, planned, testData.expectedPlanned)
+			}
+		})
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"time"
 
 	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
 	"github.com/ashokhin/am4bot/internal/utils"
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/chromedp"
 )
 
+func init() {
+	RegisterService(service{name: "marketing", run: (*Bot).marketingCompanies})
+}
+
 var marketingCompaniesMap = map[string]model.MarketingCompany{
 	"AirlineReputation": {
 		Name:               "Airline reputation",
@@ -41,12 +44,12 @@ func (b *Bot) marketingCompanies(ctx context.Context) error {
 	slog.Info("check marketing companies")
 
 	// open finance pop-up
-	utils.DoClickElement(ctx, model.BUTTON_MAIN_FINANCE)
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	b.doClick(ctx, model.BUTTON_MAIN_FINANCE)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 	// open the "+ New campaign" section
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_COMMON_TAB2),
-		utils.ClickElement(model.BUTTON_FINANCE_MARKETING_NEW_COMPANY),
+	if err := b.clickAll(ctx,
+		model.BUTTON_COMMON_TAB2,
+		model.BUTTON_FINANCE_MARKETING_NEW_COMPANY,
 	); err != nil {
 		slog.Warn("error in Bot.marketingCompanies > open marketing companies window", "error", err)
 
@@ -91,6 +94,7 @@ func (b *Bot) marketingCompanies(ctx context.Context) error {
 
 			// update Prometheus metrics
 			b.PrometheusMetrics.MarketingCompanyDurationSeconds.WithLabelValues(markComp.Name).Set(float64(markComp.DurationSeconds))
+			b.setMarketingStatus(markComp)
 		}
 	}
 
@@ -99,14 +103,11 @@ func (b *Bot) marketingCompanies(ctx context.Context) error {
 
 // checkMarketingCompanyStatus checks if a marketing company is currently active.
 func (b *Bot) checkMarketingCompanyStatus(ctx context.Context, mc *model.MarketingCompany) error {
-	var marketingCompanyElemAttributes map[string]string
-
 	slog.Debug("Check marketing company by the 'class' attribute", "company", mc.Name)
 
 	// search marketingCompany element attributes
-	if err := chromedp.Run(ctx,
-		chromedp.Attributes(mc.CompanyRow, &marketingCompanyElemAttributes, chromedp.ByQuery),
-	); err != nil {
+	marketingCompanyElemAttributes, err := b.Page.Attributes(ctx, mc.CompanyRow)
+	if err != nil {
 		slog.Warn("error in Bot.activateMarketingCompany > get company elem attributes", "company", mc.Name, "error", err)
 
 		return err
@@ -132,37 +133,31 @@ func (b *Bot) checkMarketingCompanyStatus(ctx context.Context, mc *model.Marketi
 func (b *Bot) activateMarketingCompany(ctx context.Context, mc *model.MarketingCompany) error {
 	slog.Debug("activate marketing company", "company", mc.Name)
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_COMMON_TAB2),
-		utils.ClickElement(model.BUTTON_FINANCE_MARKETING_NEW_COMPANY),
-		utils.ClickElement(mc.CompanyRow),
+	if err := b.clickAll(ctx,
+		model.BUTTON_COMMON_TAB2,
+		model.BUTTON_FINANCE_MARKETING_NEW_COMPANY,
+		mc.CompanyRow,
 	); err != nil {
 		slog.Warn("error in Bot.activateMarketingCompany > click company row", "error", err)
 
 		return err
 	}
 
-	var marketingCompanyCost float64
-	// get marketing company cost
-	switch mc.Name {
 	// in case of "Eco friendly" marketing company we skip "select option" actions
-	case "Eco friendly":
-		if err := chromedp.Run(ctx,
-			utils.GetFloatFromElement(mc.CompanyCost, &marketingCompanyCost),
-		); err != nil {
-			slog.Warn("error in Bot.activateMarketingCompany > get company cost", "company", mc.Name, "error", err)
+	if mc.Name != "Eco friendly" {
+		if err := b.Page.SetValue(ctx, model.SELECT_FINANCE_MARKETING_COMPANY_DURATION, mc.CompanyOptionValue); err != nil {
+			slog.Warn("error in Bot.activateMarketingCompany > select company duration", "company", mc.Name, "error", err)
 
 			return err
 		}
-	default:
-		if err := chromedp.Run(ctx,
-			chromedp.SetValue(model.SELECT_FINANCE_MARKETING_COMPANY_DURATION, mc.CompanyOptionValue, chromedp.ByQuery),
-			utils.GetFloatFromElement(mc.CompanyCost, &marketingCompanyCost),
-		); err != nil {
-			slog.Warn("error in Bot.activateMarketingCompany > get company cost", "company", mc.Name, "error", err)
+	}
 
-			return err
-		}
+	// get marketing company cost
+	marketingCompanyCost, err := b.Page.Float(ctx, mc.CompanyCost)
+	if err != nil {
+		slog.Warn("error in Bot.activateMarketingCompany > get company cost", "company", mc.Name, "error", err)
+
+		return err
 	}
 
 	slog.Debug("company cost", "company", mc.Name, "cost", int(marketingCompanyCost))
@@ -175,9 +170,15 @@ func (b *Bot) activateMarketingCompany(ctx context.Context, mc *model.MarketingC
 	}
 
 	// buy marketing company
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(mc.CompanyButton),
-	); err != nil {
+	bought, err := b.buy(model.Purchase{
+		Item:     "campaign",
+		Details:  mc.Name,
+		Quantity: 1,
+		Total:    marketingCompanyCost,
+	}, func() error {
+		return b.Page.ClickWait(ctx, mc.CompanyButton)
+	})
+	if err != nil {
 		slog.Warn("error in Bot.activateMarketingCompany > buy company", "company", mc.Name, "error", err)
 
 		return err
@@ -186,6 +187,12 @@ func (b *Bot) activateMarketingCompany(ctx context.Context, mc *model.MarketingC
 	// update budgets and account balance
 	b.BudgetMoney.Marketing -= marketingCompanyCost
 	b.AccountBalance -= marketingCompanyCost
+
+	// in the dry run mode the company stays inactive
+	if !bought {
+		return nil
+	}
+
 	mc.IsActive = true
 
 	slog.Info("marketing company activated", "company", mc.Name,
@@ -205,11 +212,15 @@ func (b *Bot) collectMarketingCompanyDuration(ctx context.Context, mc *model.Mar
 	var durationStr string
 
 	// find marketing companies array
-	var marketingCompaniesList []*cdp.Node
+	var marketingCompaniesList []page.Node
+
+	if err := runSteps(
+		func() error { return b.Page.ClickWait(ctx, model.BUTTON_COMMON_TAB2) },
+		func() (err error) {
+			marketingCompaniesList, err = b.Page.Nodes(ctx, model.LIST_FINANCE_MARKETING_COMPANIES)
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_COMMON_TAB2),
-		chromedp.Nodes(model.LIST_FINANCE_MARKETING_COMPANIES, &marketingCompaniesList, chromedp.ByQueryAll),
+			return err
+		},
 	); err != nil {
 		slog.Warn("error in Bot.collectMarketingCompanyDuration > get marketing companies list", "error", err)
 
@@ -217,11 +228,8 @@ func (b *Bot) collectMarketingCompanyDuration(ctx context.Context, mc *model.Mar
 	}
 	// get marketing company duration string
 	for _, companyElem := range marketingCompaniesList {
-		var companyName string
-
-		if err := chromedp.Run(ctx,
-			chromedp.Text(model.TEXT_MARKETING_COMPANY_NAME, &companyName, chromedp.ByQuery, chromedp.FromNode(companyElem)),
-		); err != nil {
+		companyName, err := companyElem.Text(ctx, model.TEXT_MARKETING_COMPANY_NAME)
+		if err != nil {
 			slog.Warn("error in Bot.collectMarketingCompanyDuration > get company name from list", "error", err)
 
 			return err
@@ -236,9 +244,7 @@ func (b *Bot) collectMarketingCompanyDuration(ctx context.Context, mc *model.Mar
 
 			// add several attempts to get duration string due to possible UI updates
 			for i := 0; i < 5; i++ {
-				if err := chromedp.Run(ctx,
-					chromedp.Text(model.TEXT_MARKETING_COMPANY_DURATION, &durationStr, chromedp.ByQuery, chromedp.FromNode(companyElem)),
-				); err != nil {
+				if durationStr, err = companyElem.Text(ctx, model.TEXT_MARKETING_COMPANY_DURATION); err != nil {
 					slog.Warn("error in Bot.collectMarketingCompanyDuration > get company duration string", "company", mc.Name, "error", err)
 
 					return err
@@ -6,28 +6,30 @@ import (
 	"strings"
 
 	"github.com/ashokhin/am4bot/internal/model"
-	"github.com/ashokhin/am4bot/internal/utils"
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/chromedp"
+	"github.com/ashokhin/am4bot/internal/page"
 )
 
 // money checks account balances and updates the bot's budget allocations accordingly.
 func (b *Bot) money(ctx context.Context) error {
-	var accElemList []*cdp.Node
+	var accElemList []page.Node
 
 	slog.Info("check account money")
 	slog.Debug("get accounts list")
 
-	if err := chromedp.Run(ctx,
-		utils.ClickElement(model.BUTTON_MAIN_ACCOUNT),
-		chromedp.Nodes(model.LIST_ACCOUNT_ACCOUNTS, &accElemList, chromedp.ByQueryAll),
+	if err := runSteps(
+		func() error { return b.Page.ClickWait(ctx, model.BUTTON_MAIN_ACCOUNT) },
+		func() (err error) {
+			accElemList, err = b.Page.Nodes(ctx, model.LIST_ACCOUNT_ACCOUNTS)
+
+			return err
+		},
 	); err != nil {
 		slog.Warn("error in bot.Money > get accounts list", "error", err)
 
 		return err
 	}
 
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 
 	for idx, accountElem := range accElemList {
 		var accountName string
@@ -35,9 +37,17 @@ func (b *Bot) money(ctx context.Context) error {
 
 		slog.Debug("check account", "index", idx)
 
-		if err := chromedp.Run(ctx,
-			chromedp.Text(model.TEXT_ACCOUNT_ACCOUNT_NAME, &accountName, chromedp.ByQuery, chromedp.FromNode(accountElem)),
-			utils.GetFloatFromChildElement(model.TEXT_ACCOUNT_ACCOUNT_BALANCE, &accountBalance, accountElem),
+		if err := runSteps(
+			func() (err error) {
+				accountName, err = accountElem.Text(ctx, model.TEXT_ACCOUNT_ACCOUNT_NAME)
+
+				return err
+			},
+			func() (err error) {
+				accountBalance, err = accountElem.Float(ctx, model.TEXT_ACCOUNT_ACCOUNT_BALANCE)
+
+				return err
+			},
 		); err != nil {
 			slog.Warn("error in Bot.Money > get account info", "error", err)
 
@@ -67,13 +77,21 @@ func (b *Bot) calcBudget() {
 
 	b.BudgetMoney.Maintenance = (b.AccountBalance * (b.Conf.BudgetPercent.Maintenance * 0.01))
 	b.BudgetMoney.Marketing = (b.AccountBalance * (b.Conf.BudgetPercent.Marketing * 0.01))
-	b.BudgetMoney.Fuel = (b.AccountBalance * (b.Conf.BudgetPercent.Fuel * 0.01))
+	fuelPercent, _ := b.Conf.FuelTypeBudgetPercent("fuel")
+	b.BudgetMoney.Fuel = (b.AccountBalance * (fuelPercent * 0.01))
+	b.BudgetMoney.Co2 = 0
+
+	// CO2 without its own budget percent is bought from the fuel budget
+	if co2Percent, ok := b.Conf.FuelTypeBudgetPercent("co2"); ok {
+		b.BudgetMoney.Co2 = (b.AccountBalance * (co2Percent * 0.01))
+	}
 
 	slog.Debug("calculated budget",
 		"maintenancePercent", b.Conf.BudgetPercent.Maintenance,
 		"maintenanceBudget", int(b.BudgetMoney.Maintenance),
 		"marketingPercent", b.Conf.BudgetPercent.Marketing,
 		"marketingBudget", int(b.BudgetMoney.Marketing),
-		"fuelPercent", b.Conf.BudgetPercent.Fuel,
-		"fuelBudget", int(b.BudgetMoney.Fuel))
+		"fuelPercent", fuelPercent,
+		"fuelBudget", int(b.BudgetMoney.Fuel),
+		"co2Budget", int(b.BudgetMoney.Co2))
 }
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+)
+
+func TestMoney(t *testing.T) {
+	testCases := map[string]struct {
+		accounts        map[string]string
+		expectedBalance float64
+		expectedBudget  BudgetType
+	}{
+		"test01": {
+			map[string]string{"Airline account": "$ 1,000,000", "Savings": "$ 5,000"},
+			1000000,
+			BudgetType{Maintenance: 500000, Marketing: 700000, Fuel: 700000},
+		},
+		"test02": {
+			map[string]string{" Airline account ": "$ 10,000"},
+			10000,
+			BudgetType{Maintenance: 5000, Marketing: 7000, Fuel: 7000},
+		},
+		"test03": {
+			map[string]string{"Savings": "$ 5,000"},
+			0,
+			BudgetType{},
+		},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b, fakePage := newTestBot(t)
+
+			for name, balance := range testData.accounts {
+				fakePage.Lists[model.LIST_ACCOUNT_ACCOUNTS] = append(fakePage.Lists[model.LIST_ACCOUNT_ACCOUNTS], &page.FakeNode{
+					Texts: map[string]string{
+						model.TEXT_ACCOUNT_ACCOUNT_NAME:    name,
+						model.TEXT_ACCOUNT_ACCOUNT_BALANCE: balance,
+					},
+				})
+			}
+
+			if err := b.money(t.Context()); err != nil {
+				t.Fatalf("money() returned error: %v", err)
+			}
+
+			if b.AccountBalance != testData.expectedBalance {
+				t.Errorf(`money() set balance '%f', expected '%f'`, b.AccountBalance, testData.expectedBalance)
+			}
+
+			// budgets are compared with the cent precision
+			budget := BudgetType{
+				Maintenance: math.Round(b.BudgetMoney.Maintenance*100) / 100,
+				Marketing:   math.Round(b.BudgetMoney.Marketing*100) / 100,
+				Fuel:        math.Round(b.BudgetMoney.Fuel*100) / 100,
+			}
+
+			if budget != testData.expectedBudget {
+				t.Errorf(`money() set budget '%+v', expected '%+v'`, b.BudgetMoney, testData.expectedBudget)
+			}
+		})
+	}
+}
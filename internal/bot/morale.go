@@ -5,11 +5,14 @@ import (
 	"log/slog"
 
 	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
 	"github.com/ashokhin/am4bot/internal/utils"
-
-	"github.com/chromedp/chromedp"
 )
 
+func init() {
+	RegisterService(service{name: "staff_morale", run: (*Bot).staffMorale})
+}
+
 // staffMorale checks and adjusts the morale of various staff members in the company.
 func (b *Bot) staffMorale(ctx context.Context) error {
 	var rank, trainingPoints float64
@@ -17,19 +20,24 @@ func (b *Bot) staffMorale(ctx context.Context) error {
 	slog.Info("check staff morale")
 	slog.Debug("open pop-up window", "window", "company")
 
-	if err := chromedp.Run(ctx,
-		chromedp.Click(model.BUTTON_MAIN_COMPANY, chromedp.ByQuery),
-		chromedp.WaitReady(model.TEXT_COMPANY_RANK, chromedp.ByQuery),
-		utils.GetFloatFromElement(model.TEXT_COMPANY_RANK, &rank),
-		chromedp.Click(model.BUTTON_COMMON_TAB2, chromedp.ByQuery),
-		utils.GetFloatFromElement(model.TEXT_COMPANY_STAFF_TRAINING_POINTS, &trainingPoints),
+	if err := runSteps(
+		func() error { return b.Page.Click(ctx, model.BUTTON_MAIN_COMPANY) },
+		func() error { return b.Page.WaitReady(ctx, model.TEXT_COMPANY_RANK) },
+		func() error {
+			return page.ReadFloats(ctx, b.Page, page.FloatField{Selector: model.TEXT_COMPANY_RANK, Value: &rank})
+		},
+		func() error { return b.Page.Click(ctx, model.BUTTON_COMMON_TAB2) },
+		func() error {
+			return page.ReadFloats(ctx, b.Page,
+				page.FloatField{Selector: model.TEXT_COMPANY_STAFF_TRAINING_POINTS, Value: &trainingPoints})
+		},
 	); err != nil {
 		slog.Debug("error in Bot.staffMorale", "error", err)
 
 		return err
 	}
 
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 
 	slog.Debug("rank", "value", rank)
 
@@ -52,22 +60,17 @@ func (b *Bot) staffMorale(ctx context.Context) error {
 
 // checkStaffEntry checks and adjusts the morale for a specific staff entry.
 func (b *Bot) checkStaffEntry(ctx context.Context, e model.StaffEntry) error {
-	var moralePercent int
-
-	if err := chromedp.Run(ctx,
-		utils.GetIntFromElement(e.TextMorale, &moralePercent),
-	); err != nil {
+	moralePercent, err := b.Page.Int(ctx, e.TextMorale)
+	if err != nil {
 		slog.Error("error in morale.checkStaffEntry", "error", err)
 
 		return err
 	}
-	startSalary := 0.0
 
 	slog.Debug("check salary", "entry", e.Name)
 
-	if err := chromedp.Run(ctx,
-		utils.GetFloatFromElement(e.TextSalary, &startSalary),
-	); err != nil {
+	startSalary, err := b.Page.Float(ctx, e.TextSalary)
+	if err != nil {
 		return err
 	}
 
@@ -80,13 +83,13 @@ func (b *Bot) checkStaffEntry(ctx context.Context, e model.StaffEntry) error {
 
 	if moralePercent < 100 {
 		// three clicks Up and three clicks Down before the first comparison
-		if err := chromedp.Run(ctx,
-			utils.ClickElement(e.ButtonSalaryUp),
-			utils.ClickElement(e.ButtonSalaryUp),
-			utils.ClickElement(e.ButtonSalaryUp),
-			utils.ClickElement(e.ButtonSalaryDown),
-			utils.ClickElement(e.ButtonSalaryDown),
-			utils.ClickElement(e.ButtonSalaryDown),
+		if err := b.clickAll(ctx,
+			e.ButtonSalaryUp,
+			e.ButtonSalaryUp,
+			e.ButtonSalaryUp,
+			e.ButtonSalaryDown,
+			e.ButtonSalaryDown,
+			e.ButtonSalaryDown,
 		); err != nil {
 			return err
 		}
@@ -96,12 +99,10 @@ func (b *Bot) checkStaffEntry(ctx context.Context, e model.StaffEntry) error {
 		slog.Debug("align morale", "entry", e.Name, "moralePercent", moralePercent,
 			"newSalary", newSalary)
 
-		if err := chromedp.Run(ctx,
-			utils.ClickElement(e.ButtonSalaryUp),
-			// check morale
-			utils.GetIntFromElement(e.TextMorale, &moralePercent),
-			// check salary
-			utils.GetFloatFromElement(e.TextSalary, &newSalary),
+		if err := runSteps(
+			func() error { return b.Page.ClickWait(ctx, e.ButtonSalaryUp) },
+			// check morale and salary
+			func() error { return b.readMoraleAndSalary(ctx, e, &moralePercent, &newSalary) },
 		); err != nil {
 			slog.Error("error in morale.checkStaffEntry", "error", err)
 
@@ -115,13 +116,10 @@ func (b *Bot) checkStaffEntry(ctx context.Context, e model.StaffEntry) error {
 			slog.Debug("align salary. newSalary > startSalary", "entry", e.Name,
 				"newSalary", newSalary, "startSalary", startSalary, "attemptsLeft", maxAttempts)
 
-			if err := chromedp.Run(ctx,
-				utils.ClickElement(e.ButtonSalaryDown),
-				utils.ClickElement(e.ButtonSalaryUp),
-				// check morale
-				utils.GetIntFromElement(e.TextMorale, &moralePercent),
-				// check salary
-				utils.GetFloatFromElement(e.TextSalary, &newSalary),
+			if err := runSteps(
+				func() error { return b.clickAll(ctx, e.ButtonSalaryDown, e.ButtonSalaryUp) },
+				// check morale and salary
+				func() error { return b.readMoraleAndSalary(ctx, e, &moralePercent, &newSalary) },
 			); err != nil {
 				slog.Error("error in morale.checkStaffEntry", "error", err)
 
@@ -144,3 +142,16 @@ func (b *Bot) checkStaffEntry(ctx context.Context, e model.StaffEntry) error {
 
 	return nil
 }
+
+// readMoraleAndSalary reads the current morale percent and salary of the staff entry.
+func (b *Bot) readMoraleAndSalary(ctx context.Context, e model.StaffEntry, moralePercent *int, salary *float64) error {
+	var err error
+
+	if *moralePercent, err = b.Page.Int(ctx, e.TextMorale); err != nil {
+		return err
+	}
+
+	*salary, err = b.Page.Float(ctx, e.TextSalary)
+
+	return err
+}
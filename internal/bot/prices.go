@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/prices"
+)
+
+// newPriceHistory creates the history of the observed prices if it's enabled in the configuration.
+func newPriceHistory(conf *config.Config) *prices.History {
+	if conf.PriceHistory.File == "" {
+		slog.Debug("price history is disabled")
+
+		return nil
+	}
+
+	return prices.New(conf.PriceHistory.File)
+}
+
+// warnIgnoredPriceStrategy logs a warning for every fuel type which uses the price tiers
+// instead of the "percentile" price strategy, the tiers have priority over the strategy.
+func warnIgnoredPriceStrategy(conf *config.Config) {
+	if conf.PriceStrategy != config.PRICE_STRATEGY_PERCENTILE {
+		return
+	}
+
+	var fuelTypes []string
+
+	for _, fuelType := range []string{"fuel", "co2"} {
+		if len(conf.FuelTiers.ForType(fuelType)) > 0 {
+			fuelTypes = append(fuelTypes, fuelType)
+		}
+	}
+
+	if len(fuelTypes) > 0 {
+		slog.Warn("price strategy isn't used for the fuel types with price tiers", "strategy", conf.PriceStrategy, "types", fuelTypes)
+	}
+}
+
+// recordPrice appends the observed price of the fuel type to the price history if it's enabled.
+// Errors are only logged, so the history doesn't break buying.
+func (b *Bot) recordPrice(fuelStruct model.Fuel) {
+	if b.priceHistory == nil {
+		return
+	}
+
+	if _, err := b.priceHistory.Record(prices.Observation{
+		Timestamp: time.Now().UTC(),
+		Account:   b.Conf.Name,
+		Item:      fuelStruct.FuelType,
+		Price:     fuelStruct.Price,
+	}); err != nil {
+		slog.Warn("error in Bot.recordPrice > History.Record", "file", b.priceHistory.FilePath(), "error", err)
+	}
+}
+
+// fuelPriceThreshold returns the maximal price of buying the fuel type. With the "percentile" strategy
+// it's the percentile of the prices observed during the last days, the "good_price" is used
+// if there are not enough observations yet.
+func (b *Bot) fuelPriceThreshold(fuelType string) float64 {
+	var threshold float64
+
+	switch fuelType {
+	case "fuel":
+		threshold = b.Conf.FuelPrice.Fuel
+	case "co2":
+		threshold = b.Conf.FuelPrice.Co2
+	}
+
+	if b.Conf.PriceStrategy == config.PRICE_STRATEGY_PERCENTILE && b.priceHistory != nil {
+		if percentile, ok := b.pricePercentile(fuelType); ok {
+			threshold = percentile
+		}
+	}
+
+	b.PrometheusMetrics.FuelPriceThreshold.WithLabelValues(fuelType).Set(threshold)
+
+	return threshold
+}
+
+// pricePercentile returns the percentile of the prices of the fuel type observed during the last days.
+// It returns false if the history can't be read or there are not enough observations.
+func (b *Bot) pricePercentile(fuelType string) (float64, bool) {
+	historyConf := b.Conf.PriceHistory
+	since := time.Now().Add(-time.Duration(historyConf.Days) * 24 * time.Hour)
+
+	observations, err := b.priceHistory.Read(fuelType, since)
+	if err != nil {
+		slog.Warn("error in Bot.pricePercentile > History.Read", "file", b.priceHistory.FilePath(), "error", err)
+
+		return 0, false
+	}
+
+	if len(observations) < historyConf.MinSamples {
+		slog.Info("not enough observed prices, good_price is used", "type", fuelType,
+			"observations", len(observations), "min_samples", historyConf.MinSamples)
+
+		return 0, false
+	}
+
+	percentile, ok := prices.Percentile(observations, historyConf.Percentile)
+
+	slog.Debug("price percentile", "type", fuelType, "percentile", historyConf.Percentile,
+		"price", percentile, "observations", len(observations))
+
+	return percentile, ok
+}
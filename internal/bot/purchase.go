@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/ledger"
+	"github.com/ashokhin/am4bot/internal/model"
+)
+
+// buy performs the final "buy" action of the purchase and returns true if the purchase has been made.
+// In the dry run mode the action is skipped and the purchase is only logged as a "would buy" event.
+func (b *Bot) buy(p model.Purchase, action func() error) (bool, error) {
+	if p.UnitPrice == 0 && p.Quantity > 0 {
+		p.UnitPrice = p.Total / p.Quantity
+	}
+
+	if b.Conf.DryRun {
+		slog.Info("dry run: would buy", "service", b.currentService, "item", p.Item, "details", p.Details,
+			"quantity", p.Quantity, "unit_price", p.UnitPrice, "total", int(p.Total))
+
+		return false, nil
+	}
+
+	if err := action(); err != nil {
+		return false, err
+	}
+
+	slog.Debug("purchase done", "service", b.currentService, "item", p.Item, "details", p.Details,
+		"quantity", p.Quantity, "unit_price", p.UnitPrice, "total", int(p.Total))
+
+	b.recordPurchase(p)
+
+	return true, nil
+}
+
+// recordPurchase appends the purchase to the ledger if it's enabled.
+// Ledger errors are only logged, because the purchase itself has already been made.
+func (b *Bot) recordPurchase(p model.Purchase) {
+	if b.ledger == nil {
+		return
+	}
+
+	if err := b.ledger.Append(ledger.Entry{
+		Timestamp: time.Now().UTC(),
+		Account:   b.Conf.Name,
+		Service:   b.currentService,
+		Item:      p.Item,
+		Details:   p.Details,
+		Quantity:  p.Quantity,
+		UnitPrice: p.UnitPrice,
+		Total:     p.Total,
+	}); err != nil {
+		slog.Warn("error in Bot.recordPurchase > Ledger.Append", "file", b.ledger.FilePath(), "error", err)
+	}
+}
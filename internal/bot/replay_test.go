@@ -0,0 +1,514 @@
+package bot
+
+import (
+	"bufio"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/metrics"
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+	"github.com/chromedp/chromedp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const (
+	// REPLAY_USER and REPLAY_PASSWORD are the credentials accepted by the replay server.
+	REPLAY_USER     string = "replay@example.com"
+	REPLAY_PASSWORD string = "replay-secret"
+	// REPLAY_SESSION_COOKIE is the name of the session cookie set by the replay server after the login.
+	REPLAY_SESSION_COOKIE string = "am4_session"
+	// REPLAY_CLICK_DELAY is the click delay of the page driver. Pop-ups of the replay server
+	// handle clicks synchronously, so there is no need to wait as long as for the real game.
+	REPLAY_CLICK_DELAY time.Duration = 200 * time.Millisecond
+)
+
+// replayServer is the local stand-in of the Airline Manager game.
+// It serves pages and pop-ups from the testdata/am4 fixtures and records all game actions.
+type replayServer struct {
+	*httptest.Server
+	templates *template.Template
+	mu        sync.Mutex
+	state     replayState
+	actions   []string
+}
+
+// replayState holds the game state which is changed by actions and rendered into the fixtures.
+type replayState struct {
+	FuelHolding int
+	Co2Holding  int
+}
+
+// newReplayServer starts the replay server which is stopped at the end of the test.
+func newReplayServer(t *testing.T) *replayServer {
+	t.Helper()
+
+	templates, err := template.ParseGlob(filepath.Join("testdata", "am4", "*.html"))
+	if err != nil {
+		t.Fatalf("template.ParseGlob() returned error: %v", err)
+	}
+
+	s := &replayServer{
+		templates: templates,
+		state: replayState{
+			FuelHolding: 1000000,
+			Co2Holding:  900000,
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/action/{name}", s.handleAction)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Actions returns the sorted list of recorded game actions.
+func (s *replayServer) Actions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	actions := slices.Clone(s.actions)
+	slices.Sort(actions)
+
+	return actions
+}
+
+// handlePage serves the main page (or the login page without the session) and the "*.php" pop-ups.
+func (s *replayServer) handlePage(w http.ResponseWriter, r *http.Request) {
+	var name string
+
+	switch {
+	case r.URL.Path == "/":
+		name = "login.html"
+
+		if _, err := r.Cookie(REPLAY_SESSION_COOKIE); err == nil {
+			name = "index.html"
+		}
+	case r.URL.Path == "/alliance_detail.php" && r.URL.Query().Get("id") != "42":
+		http.NotFound(w, r)
+
+		return
+	case strings.HasSuffix(r.URL.Path, ".php"):
+		name = strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".php") + ".html"
+	}
+
+	if s.templates.Lookup(name) == nil {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.templates.ExecuteTemplate(w, name, s.state); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleAction records the game action and applies it to the game state.
+func (s *replayServer) handleAction(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch name {
+	case "login":
+		if query.Get("email") != REPLAY_USER || query.Get("pass") != REPLAY_PASSWORD {
+			http.Error(w, "wrong credentials", http.StatusForbidden)
+
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: REPLAY_SESSION_COOKIE, Value: "replay", Path: "/"})
+		s.actions = append(s.actions, name)
+
+		return
+	case "buy_fuel":
+		amount, err := strconv.Atoi(query.Get("amount"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		switch query.Get("type") {
+		case "fuel":
+			s.state.FuelHolding += amount
+		case "co2":
+			s.state.Co2Holding += amount
+		}
+	}
+
+	action := name
+	if len(query) > 0 {
+		action += "?" + query.Encode()
+	}
+
+	s.actions = append(s.actions, action)
+}
+
+// replayChromePath returns the path of the Chrome binary for the replay tests.
+// The test is skipped if Chrome isn't available.
+func replayChromePath(t *testing.T) string {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skip replay test in the short mode")
+	}
+
+	if chromePath := os.Getenv("CHROME_PATH"); chromePath != "" {
+		return chromePath
+	}
+
+	for _, name := range []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser", "chrome", "chrome-headless-shell", "headless-shell"} {
+		if chromePath, err := exec.LookPath(name); err == nil {
+			return chromePath
+		}
+	}
+
+	t.Skip("Chrome isn't found, set the CHROME_PATH environment variable to run the replay test")
+
+	return ""
+}
+
+// newReplayBot creates a new Bot instance which drives the headless Chrome against the replay server.
+func newReplayBot(t *testing.T, s *replayServer, extraConfig string) *Bot {
+	t.Helper()
+
+	chromePath := replayChromePath(t)
+	tempDir := t.TempDir()
+	confPath := filepath.Join(tempDir, "config.yaml")
+	confData := "url: " + s.URL + "/\n" +
+		"username: " + REPLAY_USER + "\n" +
+		"password: " + REPLAY_PASSWORD + "\n" +
+		"timeout_seconds: 60\n" +
+		"ledger_file: " + filepath.Join(tempDir, "ledger.jsonl") + "\n" +
+		extraConfig
+
+	if err := os.WriteFile(confPath, []byte(confData), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	conf, err := config.New(confPath)
+	if err != nil {
+		t.Fatalf("config.New() returned error: %v", err)
+	}
+
+	chromeOpts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(chromePath),
+		chromedp.NoSandbox,
+		chromedp.WindowSize(1920, 1080),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.UserDataDir(filepath.Join(tempDir, "chrome")),
+	)
+
+	b := &Bot{
+		Conf:              conf,
+		Page:              &page.Chromedp{ClickDelay: REPLAY_CLICK_DELAY},
+		PrometheusMetrics: *metrics.New(),
+		ProgressChan:      make(chan struct{}, 100),
+		ledger:            newLedger(conf),
+		chromeOpts:        chromeOpts,
+		browser:           newPersistentBrowser(conf, chromeOpts),
+	}
+
+	t.Cleanup(b.Close)
+
+	return b
+}
+
+func TestReplayRun(t *testing.T) {
+	s := newReplayServer(t)
+	b := newReplayBot(t, s, "alliance_ids: [\"42\"]\n")
+
+	if err := b.Run(t.Context()); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	for _, serviceName := range b.Conf.Services {
+		if up := testutil.ToFloat64(b.PrometheusMetrics.ServiceUp.WithLabelValues(serviceName)); up != 1 {
+			t.Errorf(`service '%s' is down after the run`, serviceName)
+		}
+	}
+
+	status := b.Status()
+	if status.Running || status.LastRun == nil || !status.LastRun.Success || len(status.LastRun.Services) != len(b.Conf.Services) {
+		t.Errorf("Status() returned %+v after the successful run", status)
+	}
+
+	expectedActions := []string{
+		"acheck?count=2",
+		"buy_fuel?amount=2000000&type=fuel",
+		"campaign?type=cargo",
+		"campaign?type=eco",
+		"catering?hub=HND",
+		"claim_gift",
+		"depart",
+		"login",
+		"lounge_repair?hub=CDG",
+		"modify?reg=EI-002",
+		"repair",
+	}
+
+	if actions := s.Actions(); !slices.Equal(actions, expectedActions) {
+		t.Errorf("Run() made actions %q, expected %q", actions, expectedActions)
+	}
+
+	metricsTestCases := map[string]struct {
+		value    float64
+		expected float64
+	}{
+		"test01": {testutil.ToFloat64(b.PrometheusMetrics.CompanyMoney.WithLabelValues("Airline account")), 10000000},
+		"test02": {testutil.ToFloat64(b.PrometheusMetrics.CompanyFleetSize), 30},
+		"test03": {testutil.ToFloat64(b.PrometheusMetrics.AircraftStatus.WithLabelValues("wo_route")), 3},
+		"test04": {testutil.ToFloat64(b.PrometheusMetrics.CompanyRank), 1234},
+		"test05": {testutil.ToFloat64(b.PrometheusMetrics.AllianceContributedTotal), 12345678},
+		"test06": {testutil.ToFloat64(b.PrometheusMetrics.AllianceMemberSharePrice.WithLabelValues("102", "Bob Cargo", "42", "Replay Alliance")), -1},
+		"test07": {testutil.ToFloat64(b.PrometheusMetrics.HubStatsTotal.WithLabelValues("Tokyo (HND)", "paxDeparted")), 16000},
+		"test08": {testutil.ToFloat64(b.PrometheusMetrics.FuelPrice.WithLabelValues("co2")), 130},
+		"test09": {testutil.ToFloat64(b.PrometheusMetrics.MarketingCompanyDurationSeconds.WithLabelValues("Airline reputation")), 12310},
+	}
+
+	for testName, testData := range metricsTestCases {
+		t.Run(testName, func(t *testing.T) {
+			if testData.value != testData.expected {
+				t.Errorf(`metric value '%f', expected '%f'`, testData.value, testData.expected)
+			}
+		})
+	}
+
+	entries, err := b.ledger.Read(time.Time{})
+	if err != nil {
+		t.Fatalf("Ledger.Read() returned error: %v", err)
+	}
+
+	totals := make(map[string]float64)
+
+	for _, e := range entries {
+		totals[e.Item+" "+e.Details] += e.Total
+	}
+
+	expectedTotals := map[string]float64{
+		"a-check ":                  100000,
+		"campaign Cargo reputation": 300000,
+		"campaign Eco friendly":     500000,
+		"catering Tokyo (HND)":      150000,
+		"fuel ":                     900000,
+		"lounge Paris (CDG)":        80000,
+		"modify EI-002":             250000,
+		"repair wear >= 80%":        45000,
+	}
+
+	if !mapsEqual(totals, expectedTotals) {
+		t.Errorf("ledger totals %v, expected %v", totals, expectedTotals)
+	}
+}
+
+func TestReplayPersistentBrowser(t *testing.T) {
+	s := newReplayServer(t)
+	b := newReplayBot(t, s, "chrome_persistent: true\n")
+
+	for run := range 2 {
+		if err := b.RunServices(t.Context(), []string{"depart"}); err != nil {
+			t.Fatalf("RunServices() returned error on the run %d: %v", run, err)
+		}
+	}
+
+	// the second run reuses the authenticated page
+	if logins := countActions(s.Actions(), "login"); logins != 1 {
+		t.Errorf("RunServices() logged in %d times, expected once", logins)
+	}
+
+	if departs := countActions(s.Actions(), "depart"); departs != 2 {
+		t.Errorf("RunServices() departed %d times, expected twice", departs)
+	}
+
+	// the closed browser is started again
+	b.browser.close()
+
+	if err := b.RunServices(t.Context(), []string{"depart"}); err != nil {
+		t.Fatalf("RunServices() returned error after the browser is closed: %v", err)
+	}
+}
+
+// startRemoteChrome starts Chrome with the DevTools endpoint on a random port
+// and returns its websocket URL. Chrome is stopped at the end of the test.
+func startRemoteChrome(t *testing.T) string {
+	t.Helper()
+
+	cmd := exec.Command(replayChromePath(t),
+		"--headless",
+		"--no-sandbox",
+		"--disable-dev-shm-usage",
+		"--remote-debugging-port=0",
+		"--user-data-dir="+t.TempDir(),
+		"about:blank",
+	)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		t.Fatalf("cmd.StderrPipe() returned error: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("cmd.Start() returned error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	scanner := bufio.NewScanner(stderr)
+
+	for scanner.Scan() {
+		if wsUrl, ok := strings.CutPrefix(scanner.Text(), "DevTools listening on "); ok {
+			// drain the rest of the output, so Chrome isn't blocked on writing
+			go func() {
+				for scanner.Scan() {
+				}
+			}()
+
+			return wsUrl
+		}
+	}
+
+	t.Fatalf("Chrome didn't report the DevTools URL")
+
+	return ""
+}
+
+func TestReplayRemoteBrowser(t *testing.T) {
+	s := newReplayServer(t)
+	b := newReplayBot(t, s, "chrome_remote_url: "+startRemoteChrome(t)+"\n")
+
+	if err := b.RunServices(t.Context(), []string{"depart"}); err != nil {
+		t.Fatalf("RunServices() returned error: %v", err)
+	}
+
+	if departs := countActions(s.Actions(), "depart"); departs != 1 {
+		t.Errorf("RunServices() departed %d times, expected once", departs)
+	}
+}
+
+func TestReplayScanRoutes(t *testing.T) {
+	s := newReplayServer(t)
+	b := newReplayBot(t, s, "hubs_list: [\"Paris (CDG)\"]\n"+
+		"max_route_range_km: 6500\n"+
+		"min_route_range_km: 6500\n")
+
+	// the routes CSV file is written into the working directory
+	t.Chdir(t.TempDir())
+
+	if err := b.ScanRoutes(t.Context()); err != nil {
+		t.Fatalf("ScanRoutes() returned error: %v", err)
+	}
+
+	routes, err := os.ReadFile("routes_3001.csv")
+	if err != nil {
+		t.Fatalf("os.ReadFile() returned error: %v", err)
+	}
+
+	for _, expected := range []string{"CDG-JFK,5837,13123,540,120,40,15000,8000", "CDG-LAX,9105,12091,430,95,30,11000,6000"} {
+		if !strings.Contains(string(routes), expected) {
+			t.Errorf("routes CSV %q doesn't contain %q", routes, expected)
+		}
+	}
+
+	if _, err := os.Stat("routes_3002.csv"); err == nil {
+		t.Errorf("routes CSV is written for the hub which isn't configured")
+	}
+
+	if actions := s.Actions(); !slices.Equal(actions, []string{"login"}) {
+		t.Errorf("ScanRoutes() made actions %q, expected only login", actions)
+	}
+}
+
+func TestReplaySelftest(t *testing.T) {
+	s := newReplayServer(t)
+	b := newReplayBot(t, s, "alliance_ids: [\"42\"]\n")
+
+	results, err := b.Selftest(t.Context())
+	if err != nil {
+		t.Fatalf("Selftest() returned error: %v", err)
+	}
+
+	statuses := make(map[string]SelectorStatus)
+
+	for _, r := range results {
+		statuses[r.Name] = r.Status
+
+		if r.Status == SELECTOR_FAIL {
+			t.Errorf(`selector '%s' failed on the screen '%s': %s`, r.Name, r.Screen, r.Detail)
+		}
+	}
+
+	if len(statuses) != len(model.SelectorNames()) {
+		t.Errorf(`Selftest() returned '%d' results, expected '%d'`, len(statuses), len(model.SelectorNames()))
+	}
+
+	// the running airline reputation campaign isn't opened, the manage tab of the hub has no lounge
+	for name, expected := range map[string]SelectorStatus{
+		"BUTTON_PLAY_NOW":                                    SELECTOR_PASS,
+		"TEXT_FUEL_FUEL_PRICE":                               SELECTOR_PASS,
+		"TEXT_MAINTENANCE_AC_REG_NUMBER":                     SELECTOR_PASS,
+		"OPTION_FINANCE_MARKETING_INC_CARGO_REP_24H_VALUE":   SELECTOR_PASS,
+		"OPTION_FINANCE_MARKETING_INC_AIRLINE_REP_24H_VALUE": SELECTOR_SKIP,
+		"TEXT_HUBS_HUB_MANAGE_REPAIR_COST":                   SELECTOR_SKIP,
+	} {
+		if statuses[name] != expected {
+			t.Errorf(`selector '%s' status is '%s', expected '%s'`, name, statuses[name], expected)
+		}
+	}
+
+	if actions := s.Actions(); !slices.Equal(actions, []string{"login"}) {
+		t.Errorf("Selftest() made actions %q, expected only login", actions)
+	}
+}
+
+// countActions returns the number of the named actions.
+func countActions(actions []string, name string) int {
+	count := 0
+
+	for _, action := range actions {
+		if action == name {
+			count++
+		}
+	}
+
+	return count
+}
+
+// mapsEqual reports whether the ledger totals are equal with the cent precision.
+func mapsEqual(a, b map[string]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		expected, ok := b[key]
+		if !ok || int(value*100) != int(expected*100) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -3,7 +3,6 @@ package bot
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
 	"slices"
 	"strconv"
@@ -12,6 +11,7 @@ import (
 	"github.com/ashokhin/am4bot/internal/config"
 	"github.com/ashokhin/am4bot/internal/io"
 	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
 	"github.com/ashokhin/am4bot/internal/utils"
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/chromedp"
@@ -25,6 +25,7 @@ func NewScanner(conf *config.Config) Bot {
 
 	return Bot{
 		Conf:         conf,
+		Page:         page.NewChromedp(),
 		ProgressChan: progressCh,
 		chromeOpts:   opts,
 	}
@@ -32,24 +33,35 @@ func NewScanner(conf *config.Config) Bot {
 
 // startScanner initializes the Chrome context and performs authentication for scanning operations.
 func (b *Bot) startScanner(ctx context.Context) (context.Context, context.CancelFunc, error) {
-	var cdpLogger chromedp.ContextOption
-
 	slog.Debug("create execution context")
 
-	allocatorCtx, cancel := chromedp.NewExecAllocator(ctx, b.chromeOpts...)
+	if b.Conf.ChromeRemoteUrl != "" {
+		if err := checkRemoteBrowser(ctx, b.Conf.ChromeRemoteUrl); err != nil {
+			slog.Warn("error in Bot.startScanner > checkRemoteBrowser", "error", err)
 
-	if b.Conf.ChromeDebug {
-		cdpLogger = chromedp.WithDebugf(log.Printf)
-	} else {
-		cdpLogger = chromedp.WithLogf(log.Printf)
+			return nil, nil, err
+		}
 	}
 
+	allocatorCtx, cancel := newAllocator(b.Conf, b.chromeOpts)(ctx)
+
 	taskCtx, cancel := chromedp.NewContext(
 		allocatorCtx,
-		cdpLogger,
+		chromeLogger(b.Conf),
 	)
 
 	slog.Debug("run bot", "start_time", time.Now().UTC())
+	slog.Debug("navigate", "url", b.Conf.Url)
+
+	// open URL to initialize session and cookies before authentication
+	if err := b.Page.Navigate(taskCtx, b.Conf.Url); err != nil {
+		slog.Warn("error in Bot.startScanner navigate", "error", err)
+
+		cancel()
+
+		return nil, nil, err
+	}
+
 	slog.Info("authentication")
 
 	// perform authentication
@@ -0,0 +1,696 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/config"
+	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
+)
+
+const (
+	// SELFTEST_TIMEOUT limits the whole self-test session.
+	SELFTEST_TIMEOUT time.Duration = 10 * time.Minute
+	// SELFTEST_STEP_TIMEOUT defines how long the self-test waits for the element of a single check.
+	SELFTEST_STEP_TIMEOUT time.Duration = 10 * time.Second
+)
+
+// SelectorStatus is the result of the selector check made by the self-test.
+type SelectorStatus string
+
+const (
+	SELECTOR_PASS SelectorStatus = "PASS"
+	SELECTOR_FAIL SelectorStatus = "FAIL"
+	// SELECTOR_SKIP means that the selector isn't checked, e.g. the element isn't shown in the current game state.
+	SELECTOR_SKIP SelectorStatus = "SKIP"
+)
+
+// SelectorResult is the result of the check of a single selector from the model package.
+type SelectorResult struct {
+	Name   string
+	Screen string
+	Status SelectorStatus
+	Detail string
+}
+
+// selftestStepKind defines how the selector is checked.
+type selftestStepKind int
+
+const (
+	// STEP_CLICK clicks the element, the rest of the screen is skipped if it's not clicked.
+	STEP_CLICK selftestStepKind = iota
+	// STEP_VISIBLE checks that the element is visible.
+	STEP_VISIBLE
+	// STEP_LIST checks that at least one element matches the selector.
+	STEP_LIST
+	// STEP_NUMBER checks that the number is parsed from the element text.
+	STEP_NUMBER
+	// STEP_TEXT checks that the element text isn't empty.
+	STEP_TEXT
+	// STEP_ATTRIBUTE checks that the parent element has the attribute named by the selector.
+	STEP_ATTRIBUTE
+	// STEP_OPTION checks that the parent select element has the option with the selector's value.
+	STEP_OPTION
+	// STEP_INACTIVE checks that the element isn't marked as active (e.g. the running marketing campaign),
+	// otherwise the rest of the screen is skipped.
+	STEP_INACTIVE
+)
+
+// selftestStep is the check of a single selector. Selectors are referenced by their names,
+// so the values overridden by the selectors file are checked.
+type selftestStep struct {
+	kind selftestStepKind
+	name string
+	// parent is the name of the list selector, the selector is relative to its first element.
+	// For STEP_OPTION it's the name of the select element.
+	parent string
+	// optional elements could be missing in the current game state
+	optional bool
+}
+
+// in returns the step relative to the first element of the parent list.
+func (s selftestStep) in(parent string) selftestStep {
+	s.parent = parent
+
+	return s
+}
+
+// orSkip returns the step of the element which could be missing in the current game state.
+func (s selftestStep) orSkip() selftestStep {
+	s.optional = true
+
+	return s
+}
+
+func checkClick(name string) selftestStep     { return selftestStep{kind: STEP_CLICK, name: name} }
+func checkVisible(name string) selftestStep   { return selftestStep{kind: STEP_VISIBLE, name: name} }
+func checkList(name string) selftestStep      { return selftestStep{kind: STEP_LIST, name: name} }
+func checkNumber(name string) selftestStep    { return selftestStep{kind: STEP_NUMBER, name: name} }
+func checkText(name string) selftestStep      { return selftestStep{kind: STEP_TEXT, name: name} }
+func checkAttribute(name string) selftestStep { return selftestStep{kind: STEP_ATTRIBUTE, name: name} }
+func checkOption(name string) selftestStep    { return selftestStep{kind: STEP_OPTION, name: name} }
+func checkInactive(name string) selftestStep  { return selftestStep{kind: STEP_INACTIVE, name: name} }
+
+// selftestScreen is the sequence of checks which starts from the main screen.
+type selftestScreen struct {
+	name string
+	// tabUrl returns the URL of the page opened in a new tab for the screen.
+	// The screen is skipped if the URL is empty.
+	tabUrl func(conf *config.Config) string
+	steps  []selftestStep
+}
+
+// loginSelectors are checked by the authentication before the screens.
+var loginSelectors = []string{
+	"BUTTON_PLAY_NOW",
+	"BUTTON_LOGIN",
+	"TEXT_FIELD_LOGIN",
+	"TEXT_FIELD_PASSWORD",
+	"CHECKBOX_REMEMBER",
+	"BUTTON_AUTH",
+	"OVERLAY_LOADING",
+}
+
+// selftestScreens only open pop-ups, tabs and forms. Buttons which buy, plan, depart or claim
+// anything are checked for visibility and never clicked.
+var selftestScreens = []selftestScreen{
+	{name: "main", steps: []selftestStep{
+		checkVisible("BUTTON_MAIN_HUBS"),
+		checkVisible("BUTTON_MAIN_ACCOUNT"),
+		checkVisible("BUTTON_MAIN_COMPANY"),
+		checkVisible("BUTTON_MAIN_FLEET"),
+		checkVisible("BUTTON_MAIN_FUEL"),
+		checkVisible("BUTTON_MAIN_MAINTENANCE"),
+		checkVisible("BUTTON_MAIN_FINANCE"),
+		checkVisible("BUTTON_MAIN_BONUS"),
+		checkVisible("ICON_FREE_REWARDS").orSkip(),
+		checkVisible("ICON_FI_LOUNGE_ALERT").orSkip(),
+		checkVisible("BUTTON_ALLIANCE_INFO"),
+		checkVisible("BUTTON_FI_OVERVIEW"),
+		checkVisible("BUTTON_FI_DEPART_ALL"),
+		checkNumber("TEXT_FI_DEPART_AMOUNT"),
+	}},
+	{name: "banking", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_ACCOUNT"),
+		checkVisible("BUTTON_COMMON_CLOSE_POPUP"),
+		checkVisible("ELEMENT_COMMON_POPUP"),
+		checkList("LIST_ACCOUNT_ACCOUNTS"),
+		checkText("TEXT_ACCOUNT_ACCOUNT_NAME").in("LIST_ACCOUNT_ACCOUNTS"),
+		checkNumber("TEXT_ACCOUNT_ACCOUNT_BALANCE").in("LIST_ACCOUNT_ACCOUNTS"),
+	}},
+	{name: "overview", steps: []selftestStep{
+		checkClick("BUTTON_FI_OVERVIEW"),
+		checkNumber("TEXT_OVERVIEW_AIRLINE_REPUTATION"),
+		checkNumber("TEXT_OVERVIEW_CARGO_REPUTATION"),
+		checkNumber("TEXT_OVERVIEW_FLEET_SIZE"),
+		checkNumber("TEXT_OVERVIEW_AC_PENDING_DELIVERY"),
+		checkNumber("TEXT_OVERVIEW_ROUTES"),
+		checkNumber("TEXT_OVERVIEW_HUBS"),
+		checkNumber("TEXT_OVERVIEW_AC_PENDING_MAINTENANCE"),
+		checkNumber("TEXT_OVERVIEW_HANGAR_CAPACITY"),
+		checkNumber("TEXT_OVERVIEW_AC_INFLIGHT"),
+		checkNumber("TEXT_OVERVIEW_SHARE_PRICE"),
+		checkNumber("TEXT_OVERVIEW_FLIGHTS_OPERATED"),
+		checkNumber("TEXT_OVERVIEW_PASSENGERS_ECONOMY_TRANSPORTED"),
+		checkNumber("TEXT_OVERVIEW_PASSENGERS_BUSINESS_TRANSPORTED"),
+		checkNumber("TEXT_OVERVIEW_PASSENGERS_FIRST_TRANSPORTED"),
+		checkNumber("TEXT_OVERVIEW_CARGO_TRANSPORTED_LARGE"),
+		checkNumber("TEXT_OVERVIEW_CARGO_TRANSPORTED_HEAVY"),
+	}},
+	{name: "alliance", steps: []selftestStep{
+		checkClick("BUTTON_ALLIANCE_INFO"),
+		// the stats are shown only for members of an alliance
+		checkNumber("TEXT_ALLIANCE_CONTRIBUTED_TOTAL").orSkip(),
+		checkNumber("TEXT_ALLIANCE_CONTRIBUTED_PER_DAY").orSkip(),
+		checkNumber("TEXT_ALLIANCE_FLIGHTS").orSkip(),
+		checkNumber("TEXT_ALLIANCE_SEASON_MONEY").orSkip(),
+	}},
+	{name: "alliance page", tabUrl: allianceDetailUrl, steps: []selftestStep{
+		checkText("TEXT_ALLIANCE_PG_DETAIL_NAME"),
+		checkList("LIST_ALLIANCE_MEMBERS"),
+		checkAttribute("TEXT_ALLIANCE_MEMBER_ID").in("LIST_ALLIANCE_MEMBERS"),
+		checkText("TEXT_ALLIANCE_MEMBER_NAME").in("LIST_ALLIANCE_MEMBERS"),
+		// the share price could be "N/A"
+		checkText("TEXT_ALLIANCE_MEMBER_SHARE_PRICE").in("LIST_ALLIANCE_MEMBERS"),
+		checkNumber("TEXT_ALLIANCE_MEMBER_CONTRIBUTED_TOTAL").in("LIST_ALLIANCE_MEMBERS"),
+		checkNumber("TEXT_ALLIANCE_MEMBER_CONTRIBUTED_PER_DAY").in("LIST_ALLIANCE_MEMBERS"),
+		checkNumber("TEXT_ALLIANCE_MEMBER_FLIGHTS").in("LIST_ALLIANCE_MEMBERS"),
+		checkNumber("TEXT_ALLIANCE_MEMBER_SEASON_MONEY").in("LIST_ALLIANCE_MEMBERS"),
+	}},
+	{name: "hubs", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_HUBS"),
+		checkList("LIST_HUBS_HUBS"),
+		checkText("TEXT_HUBS_HUB_NAME").in("LIST_HUBS_HUBS"),
+		checkNumber("TEXT_HUBS_HUB_DEPARTURES").in("LIST_HUBS_HUBS"),
+		checkNumber("TEXT_HUBS_HUB_ARRIVALS").in("LIST_HUBS_HUBS"),
+		checkNumber("TEXT_HUBS_HUB_PAX_DEPARTED").in("LIST_HUBS_HUBS"),
+		checkNumber("TEXT_HUBS_HUB_PAX_ARRIVED").in("LIST_HUBS_HUBS"),
+		checkVisible("ICON_HUBS_CATERING").in("LIST_HUBS_HUBS").orSkip(),
+		checkClick("BUTTON_HUBS_LOUNGES_MAINTENANCE"),
+		checkVisible("BUTTON_HUBS_LOUNGES_BACK_TO_HUBS"),
+		// hubs could have no lounges
+		checkList("LIST_HUBS_LOUNGES").orSkip(),
+		checkText("TEXT_HUBS_LOUNGES_LOUNGE_NAME").in("LIST_HUBS_LOUNGES"),
+		checkNumber("TEXT_HUBS_LOUNGES_LOUNGE_WEAR_PERCENT").in("LIST_HUBS_LOUNGES"),
+		checkNumber("TEXT_HUBS_LOUNGES_LOUNGE_REPAIR_COST").in("LIST_HUBS_LOUNGES").orSkip(),
+		checkVisible("BUTTON_HUBS_LOUNGES_LOUNGE_REPAIR").in("LIST_HUBS_LOUNGES").orSkip(),
+	}},
+	{name: "hub catering", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_HUBS"),
+		checkClick("ELEMENT_HUB").in("LIST_HUBS_HUBS"),
+		checkVisible("BUTTON_HUBS_HUB_MANAGE_BACK"),
+		// the catering form isn't available while the catering is active
+		checkClick("BUTTON_HUBS_ADD_CATERING").orSkip(),
+		checkVisible("ELEM_HUBS_CATERING_OPTION_3"),
+		checkVisible("SELECT_HUBS_CATERING_DURATION"),
+		checkVisible("SELECT_HUBS_CATERING_AMOUNT"),
+		checkNumber("TEXT_HUBS_CATERING_COST"),
+		checkVisible("BUTTON_HUBS_CATERING_BUY"),
+	}},
+	{name: "hub management", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_HUBS"),
+		checkClick("ELEMENT_HUB").in("LIST_HUBS_HUBS"),
+		checkClick("BUTTON_HUBS_HUB_MANAGE").orSkip(),
+		// the lounge repair is shown only for hubs with the lounge
+		checkNumber("TEXT_HUBS_HUB_MANAGE_REPAIR_COST").orSkip(),
+		checkVisible("BUTTON_HUBS_HUB_MANAGE_REPAIR").orSkip(),
+	}},
+	{name: "company", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_COMPANY"),
+		checkNumber("TEXT_COMPANY_RANK"),
+		checkClick("BUTTON_COMMON_TAB2"),
+		checkNumber("TEXT_COMPANY_STAFF_TRAINING_POINTS"),
+		checkNumber("TEXT_COMPANY_STAFF_PILOT_SALARY"),
+		checkNumber("TEXT_COMPANY_STAFF_PILOT_MORALE"),
+		checkVisible("BUTTON_COMPANY_STAFF_PILOT_SALARY_UP"),
+		checkVisible("BUTTON_COMPANY_STAFF_PILOT_SALARY_DOWN"),
+		checkNumber("TEXT_COMPANY_STAFF_CREW_SALARY"),
+		checkNumber("TEXT_COMPANY_STAFF_CREW_MORALE"),
+		checkVisible("BUTTON_COMPANY_STAFF_CREW_SALARY_UP"),
+		checkVisible("BUTTON_COMPANY_STAFF_CREW_SALARY_DOWN"),
+		checkNumber("TEXT_COMPANY_STAFF_ENGINEER_SALARY"),
+		checkNumber("TEXT_COMPANY_STAFF_ENGINEER_MORALE"),
+		checkVisible("BUTTON_COMPANY_STAFF_ENGINEER_SALARY_UP"),
+		checkVisible("BUTTON_COMPANY_STAFF_ENGINEER_SALARY_DOWN"),
+		checkNumber("TEXT_COMPANY_STAFF_TECHNICIAN_SALARY"),
+		checkNumber("TEXT_COMPANY_STAFF_TECHNICIAN_MORALE"),
+		checkVisible("BUTTON_COMPANY_STAFF_TECHNICIAN_SALARY_UP"),
+		checkVisible("BUTTON_COMPANY_STAFF_TECHNICIAN_SALARY_DOWN"),
+	}},
+	{name: "fleet research", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_FLEET"),
+		checkClick("BUTTON_COMMON_TAB3"),
+		checkVisible("SELECT_FLEET_RESEARCH_DEPARTING_FROM"),
+		checkList("LIST_FLEET_RESEARCH_DEPARTING_FROM"),
+		checkVisible("TEXTFIELD_FLEET_RESEARCH_MAX_DISTANCE"),
+		checkVisible("TEXTFIELD_FLEET_RESEARCH_MIN_RUNWAY"),
+		checkClick("BUTTON_FLEET_RESEARCH_SEARCH"),
+		// the search could find no routes
+		checkList("LIST_FLEET_RESEARCH_SEARCH_RESULTS").orSkip(),
+		checkText("TEXT_FLEET_RESEARCH_ROUTE_FROM").in("LIST_FLEET_RESEARCH_SEARCH_RESULTS"),
+		checkText("TEXT_FLEET_RESEARCH_ROUTE_TO").in("LIST_FLEET_RESEARCH_SEARCH_RESULTS"),
+		checkClick("LINK_FLEET_RESEARCH_CUSTOM_DEPARTURE"),
+		checkVisible("SELECT_FLEET_RESEARCH_COUNTRY_SELECTOR"),
+		checkList("LIST_FLEET_RESEARCH_COUNTRY_OPTIONS"),
+	}},
+	{name: "fuel", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_FUEL"),
+		checkClick("BUTTON_COMMON_TAB1"),
+		checkNumber("TEXT_FUEL_FUEL_PRICE"),
+		checkNumber("TEXT_FUEL_FUEL_HOLDING"),
+		checkNumber("TEXT_FUEL_FUEL_CAPACITY"),
+		checkVisible("TEXT_FIELD_FUEL_AMOUNT"),
+		checkVisible("BUTTON_FUEL_BUY"),
+	}},
+	{name: "co2", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_FUEL"),
+		checkClick("BUTTON_COMMON_TAB2"),
+		checkNumber("TEXT_FUEL_FUEL_PRICE"),
+		checkNumber("TEXT_FUEL_FUEL_HOLDING"),
+		checkNumber("TEXT_FUEL_FUEL_CAPACITY"),
+		checkVisible("TEXT_FIELD_FUEL_AMOUNT"),
+		checkVisible("BUTTON_FUEL_BUY"),
+	}},
+	{name: "maintenance modify", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_MAINTENANCE"),
+		checkClick("BUTTON_COMMON_TAB2"),
+		checkVisible("BUTTON_MAINTENANCE_BULK_REPAIR"),
+		checkVisible("BUTTON_MAINTENANCE_BULK_ACHECK"),
+		checkClick("BUTTON_MAINTENANCE_BASE_ONLY"),
+		// all aircraft could be in flight
+		checkList("LIST_MAINTENANCE_AC_LIST").orSkip(),
+		checkAttribute("TEXT_MAINTENANCE_AC_REG_NUMBER").in("LIST_MAINTENANCE_AC_LIST"),
+		checkAttribute("TEXT_MAINTENANCE_AC_TYPE").in("LIST_MAINTENANCE_AC_LIST"),
+		checkClick("BUTTON_MAINTENANCE_MODIFY").in("LIST_MAINTENANCE_AC_LIST"),
+		checkVisible("CHECKBOX_MAINTENANCE_MODIFY_MOD1"),
+		checkVisible("CHECKBOX_MAINTENANCE_MODIFY_MOD2"),
+		checkVisible("CHECKBOX_MAINTENANCE_MODIFY_MOD3"),
+		checkNumber("TEXT_MAINTENANCE_MODIFY_TOTAL_COST"),
+		checkVisible("BUTTON_MAINTENANCE_PLAN_MODIFY"),
+	}},
+	{name: "maintenance bulk repair", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_MAINTENANCE"),
+		checkClick("BUTTON_COMMON_TAB2"),
+		checkClick("BUTTON_MAINTENANCE_BULK_REPAIR"),
+		checkVisible("SELECT_MAINTENANCE_BULK_REPAIR_PERCENT"),
+		// the cost is shown only if there are aircraft to repair
+		checkNumber("TEXT_MAINTENANCE_BULK_REPAIR_COST").orSkip(),
+		checkVisible("BUTTON_MAINTENANCE_BULK_REPAIR_PLAN").orSkip(),
+	}},
+	{name: "maintenance bulk check", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_MAINTENANCE"),
+		checkClick("BUTTON_COMMON_TAB2"),
+		checkClick("BUTTON_MAINTENANCE_BULK_ACHECK"),
+		checkList("LIST_MAINTENANCE_BULK_ACHECK_AC_LIST").orSkip(),
+		checkNumber("TEXT_MAINTENANCE_BULK_ACHECK_HOURS").in("LIST_MAINTENANCE_BULK_ACHECK_AC_LIST"),
+		checkNumber("TEXT_MAINTENANCE_BULK_ACHECK_COST"),
+		checkVisible("BUTTON_MAINTENANCE_BULK_ACHECK_PLAN"),
+	}},
+	{name: "marketing", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_FINANCE"),
+		checkClick("BUTTON_COMMON_TAB2"),
+		// there could be no active campaigns
+		checkList("LIST_FINANCE_MARKETING_COMPANIES").orSkip(),
+		checkText("TEXT_MARKETING_COMPANY_NAME").in("LIST_FINANCE_MARKETING_COMPANIES"),
+		checkText("TEXT_MARKETING_COMPANY_DURATION").in("LIST_FINANCE_MARKETING_COMPANIES"),
+		checkClick("BUTTON_FINANCE_MARKETING_NEW_COMPANY"),
+		checkVisible("ELEM_FINANCE_MARKETING_INC_AIRLINE_REP"),
+		checkVisible("ELEM_FINANCE_MARKETING_INC_CARGO_REP"),
+		checkVisible("ELEM_FINANCE_MARKETING_ECO_FRIENDLY"),
+	}},
+	{name: "airline reputation campaign", steps: marketingCampaignSteps("ELEM_FINANCE_MARKETING_INC_AIRLINE_REP",
+		checkVisible("SELECT_FINANCE_MARKETING_COMPANY_DURATION"),
+		checkOption("OPTION_FINANCE_MARKETING_INC_AIRLINE_REP_24H_VALUE").in("SELECT_FINANCE_MARKETING_COMPANY_DURATION"),
+		checkNumber("TEXT_FINANCE_MARKETING_INC_AIRLINE_REP_COST"),
+		checkVisible("BUTTON_FINANCE_MARKETING_INC_AIRLINE_REP_BUY"),
+	)},
+	{name: "cargo reputation campaign", steps: marketingCampaignSteps("ELEM_FINANCE_MARKETING_INC_CARGO_REP",
+		checkVisible("SELECT_FINANCE_MARKETING_COMPANY_DURATION"),
+		checkOption("OPTION_FINANCE_MARKETING_INC_CARGO_REP_24H_VALUE").in("SELECT_FINANCE_MARKETING_COMPANY_DURATION"),
+		checkNumber("TEXT_FINANCE_MARKETING_INC_CARGO_REP_COST"),
+		checkVisible("BUTTON_FINANCE_MARKETING_INC_CARGO_REP_BUY"),
+	)},
+	{name: "eco-friendly campaign", steps: marketingCampaignSteps("ELEM_FINANCE_MARKETING_ECO_FRIENDLY",
+		checkNumber("TEXT_FINANCE_MARKETING_ECO_FRIENDLY_COST"),
+		checkVisible("BUTTON_FINANCE_MARKETING_ECO_FRIENDLY_BUY"),
+	)},
+	{name: "bonus", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_BONUS"),
+		checkClick("BUTTON_BONUS_DUTY_FREE_TAB"),
+		// the gift could be already claimed
+		checkVisible("BUTTON_BONUS_CLAIM_GIFT").orSkip(),
+	}},
+}
+
+// marketingCampaignSteps returns the steps which open the form of the campaign from its row.
+// The form of the running campaign isn't opened, so its checks are skipped.
+func marketingCampaignSteps(row string, formSteps ...selftestStep) []selftestStep {
+	return append([]selftestStep{
+		checkClick("BUTTON_MAIN_FINANCE"),
+		checkClick("BUTTON_COMMON_TAB2"),
+		checkClick("BUTTON_FINANCE_MARKETING_NEW_COMPANY"),
+		checkInactive(row),
+		checkClick(row),
+	}, formSteps...)
+}
+
+// allianceDetailUrl returns the URL of the page of the first alliance from "alliance_ids".
+func allianceDetailUrl(conf *config.Config) string {
+	if len(conf.AllianceIDs) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%salliance_detail.php?id=%s", conf.Url, conf.AllianceIDs[0])
+}
+
+// selftestReport collects results of the checks. A selector checked by several steps
+// fails if any step fails and passes if any step passes.
+type selftestReport struct {
+	results map[string]SelectorResult
+	order   []string
+}
+
+// record adds the result of the selector check.
+func (r *selftestReport) record(screen, name string, status SelectorStatus, detail string) {
+	previous, ok := r.results[name]
+	if !ok {
+		r.order = append(r.order, name)
+	} else if statusRank(status) <= statusRank(previous.Status) {
+		return
+	}
+
+	r.results[name] = SelectorResult{Name: name, Screen: screen, Status: status, Detail: detail}
+}
+
+// statusRank returns the priority of the status when the selector is checked several times.
+func statusRank(status SelectorStatus) int {
+	switch status {
+	case SELECTOR_FAIL:
+		return 2
+	case SELECTOR_PASS:
+		return 1
+	}
+
+	return 0
+}
+
+// Selftest logs in, opens every pop-up and checks that all selectors of the game UI
+// resolve and their values are parsed. It never buys anything.
+// The results are ordered by screens; selectors which have no checks are skipped.
+// The error is returned only if the game page can't be opened.
+func (b *Bot) Selftest(ctx context.Context) ([]SelectorResult, error) {
+	// selectors aren't overridden by the config reload of other accounts during the test,
+	// so the steps read them with model.SelectorLocked
+	defer model.UseSelectors()()
+
+	report := &selftestReport{results: make(map[string]SelectorResult)}
+
+	taskCtx, cancel, fresh, err := b.newSession(ctx, SELFTEST_TIMEOUT)
+	if err != nil {
+		slog.Warn("error in Bot.Selftest > Bot.newSession", "error", err)
+
+		return nil, err
+	}
+	defer cancel()
+
+	slog.Info("start self-test")
+
+	if fresh {
+		err = b.Page.Navigate(taskCtx, b.Conf.Url)
+	} else {
+		err = b.Page.Reload(taskCtx)
+	}
+
+	if err != nil {
+		slog.Warn("error in Bot.Selftest > open page", "error", err)
+
+		return nil, err
+	}
+
+	b.selftestLogin(taskCtx, report)
+
+	for _, screen := range selftestScreens {
+		if err := b.selftestScreen(taskCtx, screen, report); err != nil {
+			slog.Warn("error in Bot.Selftest > Bot.selftestScreen", "screen", screen.name, "error", err)
+
+			return nil, err
+		}
+	}
+
+	for _, name := range model.SelectorNames() {
+		report.record("", name, SELECTOR_SKIP, "not checked")
+	}
+
+	results := make([]SelectorResult, 0, len(report.order))
+
+	for _, name := range report.order {
+		results = append(results, report.results[name])
+	}
+
+	slog.Info("self-test complete")
+
+	return results, nil
+}
+
+// selftestLogin checks the login selectors by the authentication.
+func (b *Bot) selftestLogin(ctx context.Context, report *selftestReport) {
+	status, detail := SELECTOR_PASS, ""
+
+	if !b.Page.Visible(ctx, model.BUTTON_PLAY_NOW, VISIBILITY_TIMEOUT) {
+		status, detail = SELECTOR_SKIP, "already logged in"
+	} else if err := b.auth(ctx); err != nil {
+		slog.Warn("error in Bot.selftestLogin > Bot.auth", "error", err)
+
+		status, detail = SELECTOR_FAIL, fmt.Sprintf("login failed: %v", err)
+	}
+
+	for _, name := range loginSelectors {
+		report.record("login", name, status, detail)
+	}
+}
+
+// selftestScreen runs the checks of the screen starting from the reloaded main screen.
+// If the screen's state can't be reached by the click, the rest of its checks are skipped.
+func (b *Bot) selftestScreen(ctx context.Context, screen selftestScreen, report *selftestReport) error {
+	slog.Debug("self-test screen", "screen", screen.name)
+
+	steps := screen.steps
+
+	if screen.tabUrl != nil {
+		pageUrl := screen.tabUrl(b.Conf)
+		if pageUrl == "" {
+			report.skip(screen.name, steps, "the page isn't configured")
+
+			return nil
+		}
+
+		tabCtx, cancel, err := b.Page.OpenTab(ctx, pageUrl)
+		if err != nil {
+			report.skip(screen.name, steps, fmt.Sprintf("page isn't opened: %v", err))
+
+			return nil
+		}
+		defer cancel()
+
+		ctx = tabCtx
+	} else if err := b.Page.Reload(ctx); err != nil {
+		// reset the pop-ups opened by the previous screen
+		return err
+	}
+
+	// the first elements of the parent lists
+	parents := make(map[string]page.Node)
+
+	for i, step := range steps {
+		status, detail, stop := b.selftestStep(ctx, step, parents)
+
+		report.record(screen.name, step.name, status, detail)
+
+		if stop != "" {
+			report.skip(screen.name, steps[i+1:], stop)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// skip records the selectors of the steps as skipped.
+func (r *selftestReport) skip(screen string, steps []selftestStep, detail string) {
+	for _, step := range steps {
+		r.record(screen, step.name, SELECTOR_SKIP, detail)
+	}
+}
+
+// selftestStep runs the check of the step. If the rest of the screen can't be checked,
+// the reason is returned as stop.
+func (b *Bot) selftestStep(ctx context.Context, step selftestStep, parents map[string]page.Node) (status SelectorStatus, detail string, stop string) {
+	sel, ok := model.SelectorLocked(step.name)
+	if !ok {
+		return SELECTOR_FAIL, "unknown selector", ""
+	}
+
+	var parent page.Node
+
+	if step.parent != "" && step.kind != STEP_OPTION {
+		if parent, ok = b.selftestParent(ctx, step.parent, parents); !ok {
+			status, detail = SELECTOR_SKIP, fmt.Sprintf("no elements of %s", step.parent)
+
+			if step.kind == STEP_CLICK {
+				stop = fmt.Sprintf("%s isn't clicked", step.name)
+			}
+
+			return status, detail, stop
+		}
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, SELFTEST_STEP_TIMEOUT)
+	defer cancel()
+
+	// optional elements are checked only if they are shown
+	if step.optional && !b.selftestShown(stepCtx, sel, parent) {
+		status, detail = SELECTOR_SKIP, "not shown in the current game state"
+
+		if step.kind == STEP_CLICK {
+			stop = fmt.Sprintf("%s isn't shown", step.name)
+		}
+
+		return status, detail, stop
+	}
+
+	err := b.selftestCheck(stepCtx, step, sel, parent)
+
+	switch {
+	case step.kind == STEP_INACTIVE && errors.Is(err, errSelftestActive):
+		return SELECTOR_PASS, "", fmt.Sprintf("%s is active", step.name)
+	case err != nil && step.kind == STEP_CLICK:
+		return SELECTOR_FAIL, err.Error(), fmt.Sprintf("%s isn't clicked", step.name)
+	case err != nil:
+		return SELECTOR_FAIL, err.Error(), ""
+	}
+
+	return SELECTOR_PASS, "", ""
+}
+
+// errSelftestActive is returned by the STEP_INACTIVE check of the active element.
+var errSelftestActive = errors.New("element is active")
+
+// selftestCheck checks the selector of the step.
+func (b *Bot) selftestCheck(ctx context.Context, step selftestStep, sel string, parent page.Node) error {
+	switch step.kind {
+	case STEP_CLICK:
+		if parent != nil {
+			return parent.Click(ctx, sel)
+		}
+
+		if !b.Page.Visible(ctx, sel, SELFTEST_STEP_TIMEOUT) {
+			return fmt.Errorf("element isn't visible")
+		}
+
+		return b.Page.ClickWait(ctx, sel)
+	case STEP_VISIBLE:
+		if !b.selftestShown(ctx, sel, parent) {
+			return fmt.Errorf("element isn't visible")
+		}
+	case STEP_LIST:
+		nodes, err := b.Page.Nodes(ctx, sel)
+		if err != nil {
+			return err
+		}
+
+		if len(nodes) == 0 {
+			return fmt.Errorf("no elements found")
+		}
+	case STEP_NUMBER:
+		var err error
+
+		if parent != nil {
+			_, err = parent.Float(ctx, sel)
+		} else {
+			_, err = b.Page.Float(ctx, sel)
+		}
+
+		return err
+	case STEP_TEXT:
+		var (
+			text string
+			err  error
+		)
+
+		if parent != nil {
+			text, err = parent.Text(ctx, sel)
+		} else {
+			text, err = b.Page.Text(ctx, sel)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("element text is empty")
+		}
+	case STEP_ATTRIBUTE:
+		if parent.Attribute(sel) == "" {
+			return fmt.Errorf("attribute %q is missing", sel)
+		}
+	case STEP_OPTION:
+		selectSel, _ := model.SelectorLocked(step.parent)
+
+		nodes, err := b.Page.Nodes(ctx, fmt.Sprintf(`%s > option[value="%s"]`, selectSel, sel))
+		if err != nil || len(nodes) == 0 {
+			return fmt.Errorf("option %q is not found in %s", sel, step.parent)
+		}
+	case STEP_INACTIVE:
+		attributes, err := b.Page.Attributes(ctx, sel)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(strings.Fields(attributes["class"]), "not-active") {
+			return errSelftestActive
+		}
+	}
+
+	return nil
+}
+
+// selftestShown reports whether the element is shown on the page or inside the parent element.
+func (b *Bot) selftestShown(ctx context.Context, sel string, parent page.Node) bool {
+	if parent != nil {
+		return parent.Visible(ctx, sel)
+	}
+
+	return b.Page.Visible(ctx, sel, VISIBILITY_TIMEOUT)
+}
+
+// selftestParent returns the first element of the parent list, the found elements are cached for the screen.
+func (b *Bot) selftestParent(ctx context.Context, name string, parents map[string]page.Node) (page.Node, bool) {
+	if parent, ok := parents[name]; ok {
+		return parent, true
+	}
+
+	sel, ok := model.SelectorLocked(name)
+	if !ok {
+		return nil, false
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, VISIBILITY_TIMEOUT)
+	defer cancel()
+
+	nodes, err := b.Page.Nodes(listCtx, sel)
+	if err != nil || len(nodes) == 0 {
+		return nil, false
+	}
+
+	parents[name] = nodes[0]
+
+	return nodes[0], true
+}
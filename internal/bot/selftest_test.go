@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/model"
+)
+
+func TestSelftestCoversSelectors(t *testing.T) {
+	checked := make(map[string]bool)
+
+	for _, name := range loginSelectors {
+		checked[name] = true
+	}
+
+	for _, screen := range selftestScreens {
+		for _, step := range screen.steps {
+			for _, name := range []string{step.name, step.parent} {
+				if _, ok := model.Selector(name); name != "" && !ok {
+					t.Errorf(`screen '%s' checks unknown selector '%s'`, screen.name, name)
+				}
+			}
+
+			checked[step.name] = true
+		}
+	}
+
+	for _, name := range model.SelectorNames() {
+		if !checked[name] {
+			t.Errorf(`selector '%s' isn't checked by the self-test`, name)
+		}
+	}
+}
+
+func TestSelftestScreen(t *testing.T) {
+	b, fakePage := newTestBot(t)
+	fakePage.Texts[model.BUTTON_MAIN_FUEL] = "Fuel & co2"
+	fakePage.Texts[model.TEXT_FUEL_FUEL_PRICE] = "$ 450"
+	fakePage.Texts[model.TEXT_FUEL_FUEL_HOLDING] = "unknown"
+
+	screen := selftestScreen{name: "fuel", steps: []selftestStep{
+		checkClick("BUTTON_MAIN_FUEL"),
+		checkNumber("TEXT_FUEL_FUEL_PRICE"),
+		checkNumber("TEXT_FUEL_FUEL_HOLDING"),
+		checkVisible("ICON_FREE_REWARDS").orSkip(),
+		checkClick("BUTTON_COMMON_TAB2"),
+		checkNumber("TEXT_FUEL_FUEL_CAPACITY"),
+	}}
+
+	report := &selftestReport{results: make(map[string]SelectorResult)}
+
+	if err := b.selftestScreen(t.Context(), screen, report); err != nil {
+		t.Fatalf("selftestScreen() returned error: %v", err)
+	}
+
+	testCases := map[string]struct {
+		name           string
+		expectedStatus SelectorStatus
+	}{
+		"test01": {"BUTTON_MAIN_FUEL", SELECTOR_PASS},
+		"test02": {"TEXT_FUEL_FUEL_PRICE", SELECTOR_PASS},
+		"test03": {"TEXT_FUEL_FUEL_HOLDING", SELECTOR_FAIL},
+		"test04": {"ICON_FREE_REWARDS", SELECTOR_SKIP},
+		"test05": {"BUTTON_COMMON_TAB2", SELECTOR_FAIL},
+		"test06": {"TEXT_FUEL_FUEL_CAPACITY", SELECTOR_SKIP},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			if status := report.results[testData.name].Status; status != testData.expectedStatus {
+				t.Errorf(`selector '%s' status is '%s', expected '%s'`, testData.name, status, testData.expectedStatus)
+			}
+		})
+	}
+
+	// the same selector fails if any of its checks fails
+	report.record("co2", "TEXT_FUEL_FUEL_HOLDING", SELECTOR_PASS, "")
+	report.record("co2", "TEXT_FUEL_FUEL_CAPACITY", SELECTOR_PASS, "")
+
+	if report.results["TEXT_FUEL_FUEL_HOLDING"].Status != SELECTOR_FAIL || report.results["TEXT_FUEL_FUEL_CAPACITY"].Status != SELECTOR_PASS {
+		t.Errorf(`results of the repeated checks '%+v', expected failed holding and passed capacity`, report.results)
+	}
+}
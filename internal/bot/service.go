@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ashokhin/am4bot/internal/config"
+)
+
+// Service represents a single automation which can be executed by the Bot during a run.
+type Service interface {
+	// Name returns the unique service name used in the "services" configuration option.
+	Name() string
+	// Dependencies returns names of services which must be executed before this one
+	// if they are enabled in the same run.
+	Dependencies() []string
+	// Run executes the service using the Bot's session.
+	Run(ctx context.Context, b *Bot) error
+}
+
+// servicesRegistry holds all registered services by their names.
+var servicesRegistry = make(map[string]Service)
+
+func init() {
+	config.RegisterValidator(validateServices)
+}
+
+// RegisterService adds the service to the registry.
+// It panics if a service with the same name is already registered.
+func RegisterService(s Service) {
+	if _, ok := servicesRegistry[s.Name()]; ok {
+		panic(fmt.Sprintf("service %q is already registered", s.Name()))
+	}
+
+	servicesRegistry[s.Name()] = s
+}
+
+// GetService returns the registered service by its name.
+func GetService(name string) (Service, bool) {
+	s, ok := servicesRegistry[name]
+
+	return s, ok
+}
+
+// ServiceNames returns the sorted list of all registered service names.
+func ServiceNames() []string {
+	names := make([]string, 0, len(servicesRegistry))
+
+	for name := range servicesRegistry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// UnknownServices returns names from the list which are not registered.
+func UnknownServices(names []string) []string {
+	var unknown []string
+
+	for _, name := range names {
+		if _, ok := servicesRegistry[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	return unknown
+}
+
+// validateServices checks that the services of the "services" and "schedules" options are registered.
+func validateServices(c *config.Config) []config.FieldError {
+	var errs []config.FieldError
+
+	message := fmt.Sprintf("unknown service, possible values: %s", strings.Join(ServiceNames(), ", "))
+
+	for i, name := range c.Services {
+		if _, ok := servicesRegistry[name]; !ok {
+			errs = append(errs, config.FieldError{Field: fmt.Sprintf("services[%d]", i), Message: fmt.Sprintf("%q: %s", name, message)})
+		}
+	}
+
+	names := make([]string, 0, len(c.Schedules))
+
+	for name := range c.Schedules {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := servicesRegistry[name]; !ok {
+			errs = append(errs, config.FieldError{Field: "schedules." + name, Message: message})
+		}
+	}
+
+	return errs
+}
+
+// resolveServices returns the registered services for the provided names in execution order.
+// The configured order is kept, except that enabled dependencies of a service are moved before it.
+// Unknown service names are skipped with a warning.
+func resolveServices(names []string) []Service {
+	var ordered []Service
+
+	visited := make(map[string]bool)
+
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+
+		visited[name] = true
+		s := servicesRegistry[name]
+
+		for _, dep := range s.Dependencies() {
+			// dependencies which are not enabled for this run are ignored
+			if slices.Contains(names, dep) {
+				visit(dep)
+			}
+		}
+
+		ordered = append(ordered, s)
+	}
+
+	for _, name := range names {
+		if _, ok := servicesRegistry[name]; !ok {
+			slog.Warn("unknown service", "service", name, "available_services", ServiceNames())
+
+			continue
+		}
+
+		visit(name)
+	}
+
+	return ordered
+}
+
+// service is a Service implementation which wraps a Bot method.
+type service struct {
+	name         string
+	dependencies []string
+	run          func(b *Bot, ctx context.Context) error
+}
+
+// Name returns the service name.
+func (s service) Name() string {
+	return s.name
+}
+
+// Dependencies returns the service dependencies.
+func (s service) Dependencies() []string {
+	return s.dependencies
+}
+
+// Run executes the wrapped Bot method.
+func (s service) Run(ctx context.Context, b *Bot) error {
+	return s.run(b, ctx)
+}
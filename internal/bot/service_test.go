@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ashokhin/am4bot/internal/config"
+)
+
+func TestResolveServices(t *testing.T) {
+	testCases := map[string]struct {
+		input    []string
+		expected []string
+	}{
+		"test01": {[]string{"company_stats", "buy_fuel"}, []string{"company_stats", "buy_fuel"}},
+		"test02": {[]string{"depart", "buy_fuel"}, []string{"buy_fuel", "depart"}},
+		"test03": {[]string{"depart", "marketing", "ac_maintenance"}, []string{"ac_maintenance", "depart", "marketing"}},
+		"test04": {[]string{"depart"}, []string{"depart"}},
+		"test05": {[]string{"unknown", "hubs", "hubs"}, []string{"hubs"}},
+		"test06": {[]string{}, []string{}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			result := []string{}
+
+			for _, s := range resolveServices(testData.input) {
+				result = append(result, s.Name())
+			}
+
+			if !slices.Equal(result, testData.expected) {
+				t.Errorf(`resolveServices(%+v) returned '%+v', expected '%+v'`, testData.input, result, testData.expected)
+			}
+		})
+	}
+}
+
+func TestUnknownServices(t *testing.T) {
+	testCases := map[string]struct {
+		input    []string
+		expected []string
+	}{
+		"test01": {[]string{"company_stats", "claim_rewards"}, nil},
+		"test02": {[]string{"buy_fuel", "buy_co2"}, []string{"buy_co2"}},
+		"test03": {[]string{"foo", "bar"}, []string{"foo", "bar"}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			result := UnknownServices(testData.input)
+
+			if !slices.Equal(result, testData.expected) {
+				t.Errorf(`UnknownServices(%+v) returned '%+v', expected '%+v'`, testData.input, result, testData.expected)
+			}
+		})
+	}
+}
+
+func TestValidateServices(t *testing.T) {
+	testCases := map[string]struct {
+		services       []string
+		schedules      map[string]string
+		expectedFields []string
+	}{
+		"test01": {[]string{"buy_fuel", "depart"}, map[string]string{"depart": "*/10 * * * *"}, nil},
+		"test02": {[]string{"buy_fuel", "fly_to_moon"}, nil, []string{"services[1]"}},
+		"test03": {[]string{"depart"}, map[string]string{"depatr": "*/10 * * * *"}, []string{"schedules.depatr"}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			var fields []string
+
+			for _, fieldErr := range validateServices(&config.Config{Services: testData.services, Schedules: testData.schedules}) {
+				fields = append(fields, fieldErr.Field)
+			}
+
+			if !slices.Equal(fields, testData.expectedFields) {
+				t.Errorf(`invalid fields '%+v', expected '%+v'`, fields, testData.expectedFields)
+			}
+		})
+	}
+}
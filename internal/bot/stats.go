@@ -8,12 +8,15 @@ import (
 	"strings"
 
 	"github.com/ashokhin/am4bot/internal/model"
+	"github.com/ashokhin/am4bot/internal/page"
 	"github.com/ashokhin/am4bot/internal/utils"
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/cdproto/target"
-	"github.com/chromedp/chromedp"
 )
 
+func init() {
+	RegisterService(service{name: "company_stats", run: (*Bot).companyStats})
+	RegisterService(service{name: "alliance_stats", run: (*Bot).allianceStats})
+}
+
 // companyStats retrieves and updates various statistics about the company.
 func (b *Bot) companyStats(ctx context.Context) error {
 	var (
@@ -38,27 +41,30 @@ func (b *Bot) companyStats(ctx context.Context) error {
 	slog.Info("check company stats")
 	slog.Debug("open pop-up window", "window", "overview")
 
-	if err := chromedp.Run(ctx,
-		chromedp.Click(model.BUTTON_FI_OVERVIEW, chromedp.ByQuery),
-		chromedp.WaitReady(model.TEXT_OVERVIEW_AIRLINE_REPUTATION, chromedp.ByQuery),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_AIRLINE_REPUTATION, &airlineReputation),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_CARGO_REPUTATION, &cargoReputation),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_FLEET_SIZE, &fleetSize),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_AC_PENDING_DELIVERY, &acPendingDelivery),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_ROUTES, &routes),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_HUBS, &hubs),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_AC_PENDING_MAINTENANCE, &acPendingMaintenance),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_HANGAR_CAPACITY, &hangarCapacity),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_AC_INFLIGHT, &acInflight),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_SHARE_PRICE, &sharePrice),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_FLIGHTS_OPERATED, &flightsOperated),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_PASSENGERS_ECONOMY_TRANSPORTED, &passengersEconomyTransported),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_PASSENGERS_BUSINESS_TRANSPORTED, &passengersBusinessTransported),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_PASSENGERS_FIRST_TRANSPORTED, &passengersFirstTransported),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_CARGO_TRANSPORTED_LARGE, &cargoTransportedLarge),
-		utils.GetFloatFromElement(model.TEXT_OVERVIEW_CARGO_TRANSPORTED_HEAVY, &cargoTransportedHeavy),
-		chromedp.Click(model.BUTTON_COMMON_CLOSE_POPUP, chromedp.ByQuery),
-		//utils.Screenshot(),
+	if err := runSteps(
+		func() error { return b.Page.Click(ctx, model.BUTTON_FI_OVERVIEW) },
+		func() error { return b.Page.WaitReady(ctx, model.TEXT_OVERVIEW_AIRLINE_REPUTATION) },
+		func() error {
+			return page.ReadFloats(ctx, b.Page,
+				page.FloatField{Selector: model.TEXT_OVERVIEW_AIRLINE_REPUTATION, Value: &airlineReputation},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_CARGO_REPUTATION, Value: &cargoReputation},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_FLEET_SIZE, Value: &fleetSize},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_AC_PENDING_DELIVERY, Value: &acPendingDelivery},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_ROUTES, Value: &routes},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_HUBS, Value: &hubs},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_AC_PENDING_MAINTENANCE, Value: &acPendingMaintenance},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_HANGAR_CAPACITY, Value: &hangarCapacity},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_AC_INFLIGHT, Value: &acInflight},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_SHARE_PRICE, Value: &sharePrice},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_FLIGHTS_OPERATED, Value: &flightsOperated},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_PASSENGERS_ECONOMY_TRANSPORTED, Value: &passengersEconomyTransported},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_PASSENGERS_BUSINESS_TRANSPORTED, Value: &passengersBusinessTransported},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_PASSENGERS_FIRST_TRANSPORTED, Value: &passengersFirstTransported},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_CARGO_TRANSPORTED_LARGE, Value: &cargoTransportedLarge},
+				page.FloatField{Selector: model.TEXT_OVERVIEW_CARGO_TRANSPORTED_HEAVY, Value: &cargoTransportedHeavy},
+			)
+		},
+		func() error { return b.Page.Click(ctx, model.BUTTON_COMMON_CLOSE_POPUP) },
 	); err != nil {
 		slog.Debug("error in Bot.companyStats", "error", err)
 
@@ -118,27 +124,25 @@ func (b *Bot) allianceStats(ctx context.Context) error {
 	// collect and set Prometheus metrics for personal alliance overview stats (total contributed, contributed per day, flights, season money)
 	slog.Debug("open pop-up window", "window", "alliance_overview")
 
-	if err := chromedp.Run(ctx,
-		chromedp.Click(model.BUTTON_ALLIANCE_INFO, chromedp.ByQuery),
-	); err != nil {
+	if err := b.Page.Click(ctx, model.BUTTON_ALLIANCE_INFO); err != nil {
 		slog.Debug("error in Bot.allianceStats", "error", err)
 
 		return err
 	}
 
-	defer utils.DoClickElement(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
+	defer b.doClick(ctx, model.BUTTON_COMMON_CLOSE_POPUP)
 
-	if !utils.IsElementVisible(ctx, model.TEXT_ALLIANCE_CONTRIBUTED_TOTAL) {
+	if !b.Page.Visible(ctx, model.TEXT_ALLIANCE_CONTRIBUTED_TOTAL, VISIBILITY_TIMEOUT) {
 		slog.Warn("no alliance stats available")
 
 		return nil
 	}
 
-	if err := chromedp.Run(ctx,
-		utils.GetFloatFromElement(model.TEXT_ALLIANCE_CONTRIBUTED_TOTAL, &contributedTotal),
-		utils.GetFloatFromElement(model.TEXT_ALLIANCE_CONTRIBUTED_PER_DAY, &contributedPerDay),
-		utils.GetFloatFromElement(model.TEXT_ALLIANCE_FLIGHTS, &allianceFlights),
-		utils.GetFloatFromElement(model.TEXT_ALLIANCE_SEASON_MONEY, &seasonMoney),
+	if err := page.ReadFloats(ctx, b.Page,
+		page.FloatField{Selector: model.TEXT_ALLIANCE_CONTRIBUTED_TOTAL, Value: &contributedTotal},
+		page.FloatField{Selector: model.TEXT_ALLIANCE_CONTRIBUTED_PER_DAY, Value: &contributedPerDay},
+		page.FloatField{Selector: model.TEXT_ALLIANCE_FLIGHTS, Value: &allianceFlights},
+		page.FloatField{Selector: model.TEXT_ALLIANCE_SEASON_MONEY, Value: &seasonMoney},
 	); err != nil {
 		slog.Debug("error in Bot.allianceStats", "error", err)
 
@@ -154,37 +158,20 @@ func (b *Bot) allianceStats(ctx context.Context) error {
 }
 
 func (b *Bot) allianceStatsByID(ctx context.Context, allianceID string) (map[string]model.AllianceMember, error) {
-	var err error
-
-	alStatsCtx, cancel := chromedp.NewContext(ctx)
-	defer cancel()
-
 	slog.Debug("check alliance stats by id", "allianceID", allianceID)
 
 	pageURL := fmt.Sprintf("%salliance_detail.php?id=%s", b.Conf.Url, allianceID)
 
-	slog.Debug("navigate to alliance page", "url", pageURL)
+	slog.Debug("open alliance page in a new tab", "url", pageURL)
 
-	if err = chromedp.Run(alStatsCtx,
-		chromedp.Navigate(pageURL),
-	); err != nil {
-		slog.Warn("error in Bot.allianceStatsByID > navigate to alliance page", "allianceID", allianceID, "error", err)
+	alStatsCtx, cancel, err := b.Page.OpenTab(ctx, pageURL)
+	if err != nil {
+		slog.Warn("error in Bot.allianceStatsByID > open alliance page", "allianceID", allianceID, "error", err)
 
 		return nil, err
 	}
 
-	// activate alliance page target because it opens in a new tab
-	c := chromedp.FromContext(alStatsCtx)
-
-	slog.Debug("activate alliance page target", "allianceID", allianceID)
-
-	if err = chromedp.Run(alStatsCtx,
-		target.ActivateTarget(c.Target.TargetID),
-	); err != nil {
-		slog.Warn("error in Bot.allianceStatsByID > activate target", "allianceID", allianceID, "error", err)
-
-		return nil, err
-	}
+	defer cancel()
 
 	// collect stats for all alliance members
 	allianceMembersMap, err := b.wholeAllianceStats(alStatsCtx, allianceID)
@@ -199,12 +186,8 @@ func (b *Bot) allianceStatsByID(ctx context.Context, allianceID string) (map[str
 
 // wholeAllianceStats collects statistics about all alliance members
 func (b *Bot) wholeAllianceStats(ctx context.Context, allianceID string) (map[string]model.AllianceMember, error) {
-	var err error
-	var allianceName string
-
-	if err = chromedp.Run(ctx,
-		chromedp.Text(model.TEXT_ALLIANCE_PG_DETAIL_NAME, &allianceName, chromedp.ByQuery),
-	); err != nil {
+	allianceName, err := b.Page.Text(ctx, model.TEXT_ALLIANCE_PG_DETAIL_NAME)
+	if err != nil {
 		slog.Warn("error in Bot.allianceStatsByID > get alliance name", "allianceID", allianceID, "error", err)
 
 		return nil, err
@@ -212,14 +195,11 @@ func (b *Bot) wholeAllianceStats(ctx context.Context, allianceID string) (map[st
 
 	slog.Debug("check whole alliance stats", "allianceID", allianceID, "allianceName", allianceName)
 
-	allianceMembersElemList := make([]*cdp.Node, 0)
-
 	slog.Debug("get list of all alliance members", "allianceID", allianceID, "allianceName", allianceName)
 
 	// get list of all alliance members
-	if err = chromedp.Run(ctx,
-		chromedp.Nodes(model.LIST_ALLIANCE_MEMBERS, &allianceMembersElemList, chromedp.ByQueryAll),
-	); err != nil {
+	allianceMembersElemList, err := b.Page.Nodes(ctx, model.LIST_ALLIANCE_MEMBERS)
+	if err != nil {
 		slog.Warn("error in Bot.wholeAllianceStats > get hubs list", "error", err)
 
 		return nil, err
@@ -235,26 +215,40 @@ func (b *Bot) wholeAllianceStats(ctx context.Context, allianceID string) (map[st
 
 		allianceMember.AllianceID = allianceID
 		allianceMember.AllianceName = allianceName
-		uid = memberElem.AttributeValue(model.TEXT_ALLIANCE_MEMBER_ID)
+		uid = memberElem.Attribute(model.TEXT_ALLIANCE_MEMBER_ID)
 		uid = strings.ReplaceAll(uid, "al-list-", "")
 
 		slog.Debug("member id", "id", uid)
 
-		if err = chromedp.Run(ctx,
-			chromedp.Text(model.TEXT_ALLIANCE_MEMBER_NAME, &allianceMember.Name, chromedp.ByQuery, chromedp.FromNode(memberElem)),
-			utils.GetFloatFromChildElement(model.TEXT_ALLIANCE_MEMBER_CONTRIBUTED_TOTAL, &allianceMember.ContributedTotal, memberElem),
-			utils.GetFloatFromChildElement(model.TEXT_ALLIANCE_MEMBER_CONTRIBUTED_PER_DAY, &allianceMember.ContributedPerDay, memberElem),
-			utils.GetIntFromChildElement(model.TEXT_ALLIANCE_MEMBER_FLIGHTS, &allianceMember.FlightsTotal, memberElem),
-			utils.GetFloatFromChildElement(model.TEXT_ALLIANCE_MEMBER_SEASON_MONEY, &allianceMember.ContributedSeason, memberElem),
+		if err = runSteps(
+			func() (err error) {
+				allianceMember.Name, err = memberElem.Text(ctx, model.TEXT_ALLIANCE_MEMBER_NAME)
+
+				return err
+			},
+			func() error {
+				return page.ReadChildFloats(ctx, memberElem,
+					page.FloatField{Selector: model.TEXT_ALLIANCE_MEMBER_CONTRIBUTED_TOTAL, Value: &allianceMember.ContributedTotal},
+					page.FloatField{Selector: model.TEXT_ALLIANCE_MEMBER_CONTRIBUTED_PER_DAY, Value: &allianceMember.ContributedPerDay},
+				)
+			},
+			func() (err error) {
+				allianceMember.FlightsTotal, err = memberElem.Int(ctx, model.TEXT_ALLIANCE_MEMBER_FLIGHTS)
+
+				return err
+			},
+			func() error {
+				return page.ReadChildFloats(ctx, memberElem,
+					page.FloatField{Selector: model.TEXT_ALLIANCE_MEMBER_SEASON_MONEY, Value: &allianceMember.ContributedSeason},
+				)
+			},
 		); err != nil {
 			slog.Warn("error in Bot.wholeAllianceStats > get member data", "error", err)
 		}
 
 		// collect share price separately
 		// bc. it could be the "N/A" string
-		if err = chromedp.Run(ctx,
-			utils.GetFloatFromChildElement(model.TEXT_ALLIANCE_MEMBER_SHARE_PRICE, &allianceMember.SharePrice, memberElem),
-		); err != nil {
+		if allianceMember.SharePrice, err = memberElem.Float(ctx, model.TEXT_ALLIANCE_MEMBER_SHARE_PRICE); err != nil {
 			slog.Debug("error in Bot.wholeAllianceStats > get member share price", "allianceMember.Name", allianceMember.Name, "error", err)
 
 			allianceMember.SharePrice = -1.0
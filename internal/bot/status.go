@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/model"
+)
+
+// RUN_HISTORY_SIZE defines how many last run results are kept in the bot status.
+const RUN_HISTORY_SIZE int = 10
+
+// ServiceResult holds the result of a single service execution.
+type ServiceResult struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RunResult holds the result of a single bot run.
+type RunResult struct {
+	StartTime       time.Time       `json:"start_time"`
+	EndTime         time.Time       `json:"end_time"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	Success         bool            `json:"success"`
+	Error           string          `json:"error,omitempty"`
+	Services        []ServiceResult `json:"services"`
+}
+
+// SkippedRun describes the run which hasn't been executed, e.g. because runs are delayed after failed runs.
+type SkippedRun struct {
+	Time        time.Time `json:"time"`
+	Services    []string  `json:"services"`
+	Reason      string    `json:"reason"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// FuelStatus holds the last checked state of the fuel or CO2.
+type FuelStatus struct {
+	Price    float64 `json:"price"`
+	Holding  float64 `json:"holding"`
+	Capacity float64 `json:"capacity"`
+	BurnRate float64 `json:"burn_rate"`
+	Decision string  `json:"decision,omitempty"`
+}
+
+// HubStatus holds the last collected statistics of the hub.
+type HubStatus struct {
+	Departures  float64 `json:"departures"`
+	Arrivals    float64 `json:"arrivals"`
+	PaxDeparted float64 `json:"pax_departed"`
+	PaxArrived  float64 `json:"pax_arrived"`
+}
+
+// MarketingStatus holds the remaining time of the active marketing company.
+type MarketingStatus struct {
+	RemainingSeconds int       `json:"remaining_seconds"`
+	CheckedAt        time.Time `json:"checked_at"`
+}
+
+// Status holds the bot state which is exposed by the control API.
+type Status struct {
+	Running        bool                       `json:"running"`
+	LastRun        *RunResult                 `json:"last_run,omitempty"`
+	Runs           []RunResult                `json:"runs"`
+	LastSkipped    *SkippedRun                `json:"last_skipped,omitempty"`
+	AccountBalance float64                    `json:"account_balance"`
+	BudgetMoney    BudgetType                 `json:"budget"`
+	Fuel           map[string]FuelStatus      `json:"fuel"`
+	Hubs           map[string]HubStatus       `json:"hubs"`
+	Marketing      map[string]MarketingStatus `json:"marketing"`
+}
+
+// Status returns the snapshot of the bot state.
+func (b *Bot) Status() Status {
+	b.statusMu.Lock()
+	defer b.statusMu.Unlock()
+
+	status := b.status
+	status.Runs = make([]RunResult, len(b.status.Runs))
+
+	// run results are never changed after the run, so only slices are copied
+	for i, run := range b.status.Runs {
+		run.Services = slices.Clone(run.Services)
+		status.Runs[i] = run
+	}
+
+	if len(status.Runs) > 0 {
+		status.LastRun = &status.Runs[0]
+	}
+
+	status.Fuel = maps.Clone(b.status.Fuel)
+	status.Hubs = maps.Clone(b.status.Hubs)
+	status.Marketing = maps.Clone(b.status.Marketing)
+
+	return status
+}
+
+// SkipRun records the run of the services which is skipped, e.g. by the circuit breaker,
+// so the skip of the run requested with the API is visible in the status.
+func (b *Bot) SkipRun(services []string, reason string, nextAttempt time.Time) {
+	b.statusMu.Lock()
+	defer b.statusMu.Unlock()
+
+	// the skipped run is never changed after it's recorded, so Status doesn't copy it
+	b.status.LastSkipped = &SkippedRun{
+		Time:        time.Now(),
+		Services:    slices.Clone(services),
+		Reason:      reason,
+		NextAttempt: nextAttempt,
+	}
+}
+
+// startRun marks the bot as running and returns the result of the new run.
+func (b *Bot) startRun() *RunResult {
+	b.statusMu.Lock()
+	defer b.statusMu.Unlock()
+
+	b.status.Running = true
+
+	return &RunResult{StartTime: time.Now(), Services: []ServiceResult{}}
+}
+
+// finishRun stores the result of the completed run together with the current account state.
+func (b *Bot) finishRun(result *RunResult, err error) {
+	result.EndTime = time.Now()
+	result.DurationSeconds = result.EndTime.Sub(result.StartTime).Seconds()
+	result.Success = err == nil
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	b.statusMu.Lock()
+	defer b.statusMu.Unlock()
+
+	b.status.Running = false
+	// keep the newest run first
+	b.status.Runs = slices.Insert(b.status.Runs, 0, *result)
+
+	if len(b.status.Runs) > RUN_HISTORY_SIZE {
+		b.status.Runs = b.status.Runs[:RUN_HISTORY_SIZE]
+	}
+
+	b.status.AccountBalance = b.AccountBalance
+	b.status.BudgetMoney = b.BudgetMoney
+}
+
+// setFuelStatus stores the last checked state of the fuel type.
+func (b *Bot) setFuelStatus(fuel model.Fuel) {
+	b.statusMu.Lock()
+	defer b.statusMu.Unlock()
+
+	if b.status.Fuel == nil {
+		b.status.Fuel = make(map[string]FuelStatus)
+	}
+
+	b.status.Fuel[fuel.FuelType] = FuelStatus{
+		Price:    fuel.Price,
+		Holding:  fuel.Holding,
+		Capacity: fuel.Capacity,
+		BurnRate: fuel.BurnRate,
+		Decision: fuel.Decision,
+	}
+}
+
+// setHubStatus stores the last collected statistics of the hub.
+func (b *Bot) setHubStatus(hub model.Hub) {
+	b.statusMu.Lock()
+	defer b.statusMu.Unlock()
+
+	if b.status.Hubs == nil {
+		b.status.Hubs = make(map[string]HubStatus)
+	}
+
+	b.status.Hubs[hub.Name] = HubStatus{
+		Departures:  hub.Departures,
+		Arrivals:    hub.Arrivals,
+		PaxDeparted: hub.PaxDeparted,
+		PaxArrived:  hub.PaxArrived,
+	}
+}
+
+// setMarketingStatus stores the remaining time of the active marketing company.
+func (b *Bot) setMarketingStatus(mc model.MarketingCompany) {
+	b.statusMu.Lock()
+	defer b.statusMu.Unlock()
+
+	if b.status.Marketing == nil {
+		b.status.Marketing = make(map[string]MarketingStatus)
+	}
+
+	b.status.Marketing[mc.Name] = MarketingStatus{RemainingSeconds: mc.DurationSeconds, CheckedAt: time.Now()}
+}
+
+// addService appends the service result to the run result.
+func (r *RunResult) addService(name string, err error) {
+	serviceResult := ServiceResult{Name: name, Success: err == nil}
+
+	if err != nil {
+		serviceResult.Error = err.Error()
+	}
+
+	r.Services = append(r.Services, serviceResult)
+}
@@ -0,0 +1,149 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the state of the circuit breaker.
+type State int
+
+const (
+	// CLOSED allows runs, failed runs are retried with the exponential backoff.
+	CLOSED State = iota
+	// HALF_OPEN allows the single probe run after the site was down.
+	HALF_OPEN
+	// OPEN skips runs until the next probe.
+	OPEN
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case CLOSED:
+		return "closed"
+	case HALF_OPEN:
+		return "half-open"
+	case OPEN:
+		return "open"
+	}
+
+	return "unknown"
+}
+
+// Policy defines the backoff and circuit breaker thresholds.
+type Policy struct {
+	// InitialBackoff is the delay after the first failed run. It's doubled after every next failure.
+	InitialBackoff time.Duration
+	// MaxBackoff limits the delay between failed runs.
+	MaxBackoff time.Duration
+	// FailureThreshold is the number of consecutive failed runs which opens the breaker.
+	// The breaker is never opened if it's zero.
+	FailureThreshold int
+	// ProbeInterval is the delay between probe runs while the breaker is open.
+	ProbeInterval time.Duration
+}
+
+// Breaker decides whether the next run is allowed depending on the results of the previous runs.
+// Failed runs are delayed with the exponential backoff, and after FailureThreshold consecutive
+// failures the breaker is opened: runs are skipped and the site is probed every ProbeInterval.
+type Breaker struct {
+	mu          sync.Mutex
+	policy      Policy
+	state       State
+	failures    int
+	nextAttempt time.Time
+}
+
+// New creates a new closed Breaker with the policy.
+func New(policy Policy) *Breaker {
+	return &Breaker{policy: policy}
+}
+
+// SetPolicy replaces the policy, e.g. after the configuration is reloaded.
+// It's applied from the next recorded result.
+func (b *Breaker) SetPolicy(policy Policy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.policy = policy
+}
+
+// Allow reports whether the run is allowed at the moment.
+// The open breaker is switched to the half-open state when it's time to probe.
+func (b *Breaker) Allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Before(b.nextAttempt) {
+		return false
+	}
+
+	if b.state == OPEN {
+		b.state = HALF_OPEN
+	}
+
+	return true
+}
+
+// Record updates the breaker with the result of the run.
+func (b *Breaker) Record(now time.Time, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = CLOSED
+		b.failures = 0
+		b.nextAttempt = time.Time{}
+
+		return
+	}
+
+	b.failures++
+
+	if b.policy.FailureThreshold > 0 && b.failures >= b.policy.FailureThreshold {
+		b.state = OPEN
+		b.nextAttempt = now.Add(b.policy.ProbeInterval)
+
+		return
+	}
+
+	b.nextAttempt = now.Add(b.backoff())
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Failures returns the number of consecutive failed runs.
+func (b *Breaker) Failures() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.failures
+}
+
+// NextAttempt returns the time before which runs are not allowed.
+// It's zero if runs are allowed at any time.
+func (b *Breaker) NextAttempt() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.nextAttempt
+}
+
+// backoff returns the delay after the consecutive failures: InitialBackoff * 2^(failures-1),
+// limited by MaxBackoff.
+func (b *Breaker) backoff() time.Duration {
+	delay := b.policy.InitialBackoff
+
+	for i := 1; i < b.failures && delay < b.policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, b.policy.MaxBackoff)
+}
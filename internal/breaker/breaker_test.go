@@ -0,0 +1,94 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errRun = errors.New("site is down")
+
+func TestBackoff(t *testing.T) {
+	policy := Policy{
+		InitialBackoff:   time.Minute,
+		MaxBackoff:       10 * time.Minute,
+		FailureThreshold: 0,
+	}
+
+	testCases := map[string]struct {
+		failures int
+		expected time.Duration
+	}{
+		"test01": {1, time.Minute},
+		"test02": {2, 2 * time.Minute},
+		"test03": {4, 8 * time.Minute},
+		"test04": {5, 10 * time.Minute},
+		"test05": {30, 10 * time.Minute},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b := New(policy)
+			now := time.Now()
+
+			for range testData.failures {
+				b.Record(now, errRun)
+			}
+
+			if delay := b.NextAttempt().Sub(now); delay != testData.expected {
+				t.Errorf(`delay after '%d' failures is '%v', expected '%v'`, testData.failures, delay, testData.expected)
+			}
+
+			if b.State() != CLOSED {
+				t.Errorf(`breaker state is '%v' without the failure threshold, expected '%v'`, b.State(), CLOSED)
+			}
+		})
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	b := New(Policy{
+		InitialBackoff:   time.Minute,
+		MaxBackoff:       10 * time.Minute,
+		FailureThreshold: 3,
+		ProbeInterval:    15 * time.Minute,
+	})
+	now := time.Now()
+
+	if !b.Allow(now) {
+		t.Fatal("new breaker doesn't allow the run")
+	}
+
+	b.Record(now, errRun)
+
+	if b.Allow(now.Add(30*time.Second)) || !b.Allow(now.Add(time.Minute)) {
+		t.Errorf("run isn't delayed by the backoff after the failure")
+	}
+
+	b.Record(now, errRun)
+	b.Record(now, errRun)
+
+	if b.State() != OPEN || b.Allow(now.Add(14*time.Minute)) {
+		t.Errorf(`breaker state is '%v' after the failure threshold, expected '%v'`, b.State(), OPEN)
+	}
+
+	// the first run after the probe interval is the probe
+	if !b.Allow(now.Add(15*time.Minute)) || b.State() != HALF_OPEN {
+		t.Errorf(`breaker state is '%v' at the probe time, expected '%v'`, b.State(), HALF_OPEN)
+	}
+
+	// the failed probe opens the breaker again
+	now = now.Add(15 * time.Minute)
+	b.Record(now, errRun)
+
+	if b.State() != OPEN || !b.NextAttempt().Equal(now.Add(15*time.Minute)) {
+		t.Errorf(`breaker state is '%v' after the failed probe, expected '%v'`, b.State(), OPEN)
+	}
+
+	b.Allow(now.Add(15 * time.Minute))
+	b.Record(now.Add(15*time.Minute), nil)
+
+	if b.State() != CLOSED || b.Failures() != 0 || !b.Allow(now.Add(15*time.Minute)) {
+		t.Errorf(`breaker state is '%v' after the successful probe, expected '%v'`, b.State(), CLOSED)
+	}
+}
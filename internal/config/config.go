@@ -6,6 +6,8 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"regexp"
+	"slices"
 
 	"github.com/ashokhin/am4bot/internal/utils"
 	"github.com/creasty/defaults"
@@ -13,31 +15,63 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	// DEFAULT_ACCOUNT_NAME defines the account name of the configuration without the "accounts" list.
+	DEFAULT_ACCOUNT_NAME string = "default"
+	// PRICE_STRATEGY_FIXED buys fuel and CO2 at the price not higher than "good_price".
+	PRICE_STRATEGY_FIXED string = "fixed"
+	// PRICE_STRATEGY_PERCENTILE buys fuel and CO2 at the price in the cheapest "price_history.percentile"
+	// of the prices observed during the last "price_history.days".
+	PRICE_STRATEGY_PERCENTILE string = "percentile"
+)
+
+// accountNameRegexp defines the allowed account names, they are used in file paths and metric labels.
+var accountNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]*$`)
+
 // Config holds the configuration settings for the bot.
 type Config struct {
 	// user-configurable fields
+	Name     string `default:"default" yaml:"name"`
 	Url      string `default:"https://www.airlinemanager.com/" yaml:"url"`
 	User     string `yaml:"username"`
 	Password string `yaml:"password"`
 	LogLevel string `default:"info" yaml:"log_level"`
+	DryRun   bool   `default:"false" yaml:"dry_run"`
+	// files with the secrets, e.g. Docker secrets in "/run/secrets"
+	UserFile     string `yaml:"username_file"`
+	PasswordFile string `yaml:"password_file"`
+	ApiTokenFile string `yaml:"api_token_file"`
 	// Parameters for Bot configuration
-	BudgetPercent           BudgetType `yaml:"budget_percent"`
-	FuelPrice               Price      `yaml:"good_price"`
-	RepairLounges           bool       `default:"true" yaml:"repair_lounges"`
-	BuyCateringIfMissing    bool       `default:"true" yaml:"buy_catering_if_missing"`
-	CateringDurationHours   string     `default:"168" yaml:"catering_duration_hours"`
-	CateringAmountOption    string     `default:"20000" yaml:"catering_amount_option"`
-	HubsMaintenanceLimit    int        `default:"5" yaml:"hubs_maintenance_limit"`
-	FuelCriticalPercent     float64    `default:"20" yaml:"fuel_critical_percent"`
-	AircraftWearPercent     string     `default:"80" yaml:"aircraft_wear_percent"`
-	AircraftMaxHoursToCheck int        `default:"24" yaml:"aircraft_max_hours_to_check"`
-	AircraftModifyLimit     int        `default:"3" yaml:"aircraft_modify_limit"`
-	CronSchedule            string     `default:"*/5 * * * *" yaml:"cron_schedule"`
-	TimeoutSeconds          int        `default:"180" yaml:"timeout_seconds"`
-	Services                []string   `default:"[\"company_stats\",\"alliance_stats\",\"staff_morale\",\"hubs\",\"claim_rewards\",\"buy_fuel\",\"marketing\",\"ac_maintenance\",\"depart\"]" yaml:"services"`
-	AllianceIDs             []string   `yaml:"alliance_ids"`
-	PrometheusAddress       string     `default:":9150" yaml:"prometheus_address"`
-	PromslogConfig          *promslog.Config
+	BudgetPercent           BudgetType        `yaml:"budget_percent"`
+	FuelPrice               Price             `yaml:"good_price"`
+	PriceStrategy           string            `default:"fixed" yaml:"price_strategy"`
+	PriceHistory            PriceHistoryType  `yaml:"price_history"`
+	FuelTiers               FuelTiersType     `yaml:"fuel_tiers"`
+	RepairLounges           bool              `default:"true" yaml:"repair_lounges"`
+	BuyCateringIfMissing    bool              `default:"true" yaml:"buy_catering_if_missing"`
+	CateringDurationHours   string            `default:"168" yaml:"catering_duration_hours"`
+	CateringAmountOption    string            `default:"20000" yaml:"catering_amount_option"`
+	HubsMaintenanceLimit    int               `default:"5" yaml:"hubs_maintenance_limit"`
+	FuelCriticalPercent     float64           `default:"20" yaml:"fuel_critical_percent"`
+	FuelReserveHours        float64           `default:"0" yaml:"fuel_reserve_hours"`
+	FuelTypes               FuelTypesType     `yaml:"fuel_types"`
+	AircraftWearPercent     string            `default:"80" yaml:"aircraft_wear_percent"`
+	AircraftMaxHoursToCheck int               `default:"24" yaml:"aircraft_max_hours_to_check"`
+	AircraftModifyLimit     int               `default:"3" yaml:"aircraft_modify_limit"`
+	CronSchedule            string            `default:"*/5 * * * *" yaml:"cron_schedule"`
+	Schedules               map[string]string `yaml:"schedules"`
+	TimeoutSeconds          int               `default:"180" yaml:"timeout_seconds"`
+	ShutdownGraceSeconds    int               `default:"60" yaml:"shutdown_grace_seconds"`
+	Resilience              ResilienceType    `yaml:"resilience"`
+	Services                []string          `default:"[\"company_stats\",\"alliance_stats\",\"staff_morale\",\"hubs\",\"claim_rewards\",\"buy_fuel\",\"marketing\",\"ac_maintenance\",\"depart\"]" yaml:"services"`
+	AllianceIDs             []string          `yaml:"alliance_ids"`
+	PrometheusAddress       string            `default:":9150" yaml:"prometheus_address"`
+	LedgerFile              string            `default:"ledger.jsonl" yaml:"ledger_file"`
+	ArtifactsDir            string            `default:"artifacts" yaml:"artifacts_dir"`
+	ArtifactsKeep           int               `default:"20" yaml:"artifacts_keep"`
+	ApiToken                string            `yaml:"api_token"`
+	SelectorsFile           string            `yaml:"selectors_file"`
+	PromslogConfig          *promslog.Config  `yaml:"-"`
 	// Parameters for Scanner configuration
 	ScanType           string   `default:"route_scanner" yaml:"scan_type"`
 	HubsList           []string `yaml:"hubs_list"`
@@ -48,11 +82,35 @@ type Config struct {
 	// Parameters for both Bot and Scanner configuration
 	ChromeHeadless bool `default:"true" yaml:"chrome_headless"`
 	ChromeDebug    bool `default:"false" yaml:"chrome_debug"`
+	// Keep the browser running between runs
+	ChromePersistent bool `default:"false" yaml:"chrome_persistent"`
+	// DevTools URL of the remote browser, e.g. "ws://chrome:9222"
+	ChromeRemoteUrl string `yaml:"chrome_remote_url"`
 
 	// internal fields
 	passwordRunes  []rune // most safe storage for password in memory
 	confFilePath   string
 	configChecksum string
+	forceDryRun    bool   // dry run mode enabled from CLI, survives config reloads
+	account        string // name of the entry in the "accounts" list loaded over the top-level options
+	// files of the secrets read by the last load, their changes reload the configuration
+	secretPaths []string
+	// options from the CLI flags, they have priority over the configuration file and environment variables
+	flagOverrides map[string]string
+	// CSS selectors from the "selectors_file" keyed by their names
+	selectorOverrides map[string]string
+	// sorted names of the entries in the "accounts" list, added or removed accounts are applied only after the restart
+	accountNames []string
+}
+
+// accountsFile holds the raw entries of the "accounts" list of the configuration file.
+type accountsFile struct {
+	Accounts []yaml.Node `yaml:"accounts"`
+}
+
+// accountEntry holds the name of the entry in the "accounts" list.
+type accountEntry struct {
+	Name string `yaml:"name"`
 }
 
 // BudgetType holds budget percentage settings for various categories.
@@ -62,37 +120,169 @@ type BudgetType struct {
 	Fuel        float64 `default:"70" yaml:"fuel"`
 }
 
+// ResilienceType holds the backoff and circuit breaker settings for failed runs.
+type ResilienceType struct {
+	BackoffInitialSeconds int `default:"60" yaml:"backoff_initial_seconds"`
+	BackoffMaxSeconds     int `default:"1800" yaml:"backoff_max_seconds"`
+	FailureThreshold      int `default:"5" yaml:"failure_threshold"`
+	ProbeIntervalSeconds  int `default:"600" yaml:"probe_interval_seconds"`
+}
+
 // Price holds good price settings for fuel and CO2.
 type Price struct {
 	Fuel float64 `default:"500" yaml:"fuel"`
 	Co2  float64 `default:"120" yaml:"co2"`
 }
 
+// PriceHistoryType holds the settings of the observed prices history and the "percentile" price strategy.
+type PriceHistoryType struct {
+	File       string  `default:"prices.jsonl" yaml:"file"`
+	Days       int     `default:"7" yaml:"days"`
+	Percentile float64 `default:"20" yaml:"percentile"`
+	MinSamples int     `default:"48" yaml:"min_samples"`
+}
+
+// FuelTiersType holds the price tiers of partial purchases for fuel and CO2.
+type FuelTiersType struct {
+	Fuel []PriceTier `yaml:"fuel"`
+	Co2  []PriceTier `yaml:"co2"`
+}
+
+// PriceTier defines the level of the tank to fill up to when the price isn't higher than the maximal price.
+type PriceTier struct {
+	MaxPrice    float64 `yaml:"max_price"`
+	FillPercent float64 `yaml:"fill_percent"`
+}
+
+// ForType returns the price tiers of the fuel type: "fuel" or "co2".
+func (t FuelTiersType) ForType(fuelType string) []PriceTier {
+	switch fuelType {
+	case "fuel":
+		return t.Fuel
+	case "co2":
+		return t.Co2
+	}
+
+	return nil
+}
+
+// FuelTypesType holds the purchase settings of fuel and CO2.
+type FuelTypesType struct {
+	Fuel FuelTypeSettings `yaml:"fuel"`
+	Co2  FuelTypeSettings `yaml:"co2"`
+}
+
+// FuelTypeSettings holds the purchase settings of the fuel type.
+// Unset percentages fall back to "fuel_critical_percent" and "budget_percent.fuel".
+type FuelTypeSettings struct {
+	CriticalPercent *float64 `yaml:"critical_percent"`
+	BudgetPercent   *float64 `yaml:"budget_percent"`
+	// MaxPrice limits the price even at the critical level, zero means no limit
+	MaxPrice  float64 `yaml:"max_price"`
+	MinAmount float64 `yaml:"min_amount"`
+}
+
+// ForType returns the purchase settings of the fuel type: "fuel" or "co2".
+func (t FuelTypesType) ForType(fuelType string) FuelTypeSettings {
+	switch fuelType {
+	case "fuel":
+		return t.Fuel
+	case "co2":
+		return t.Co2
+	}
+
+	return FuelTypeSettings{}
+}
+
+// FuelTypeCriticalPercent returns the level of the fuel type (in percents) below which it's bought at any price.
+func (c *Config) FuelTypeCriticalPercent(fuelType string) float64 {
+	if percent := c.FuelTypes.ForType(fuelType).CriticalPercent; percent != nil {
+		return *percent
+	}
+
+	return c.FuelCriticalPercent
+}
+
+// FuelTypeBudgetPercent returns the percentage of the budget for the fuel type and false
+// if the fuel type has no budget of its own and shares the "budget_percent.fuel".
+func (c *Config) FuelTypeBudgetPercent(fuelType string) (float64, bool) {
+	if percent := c.FuelTypes.ForType(fuelType).BudgetPercent; percent != nil {
+		return *percent, true
+	}
+
+	return c.BudgetPercent.Fuel, false
+}
+
 // String returns a string representation of the Config struct.
 func (c Config) String() string {
-	return fmt.Sprint("{Url:", c.Url,
+	return fmt.Sprint("{Name:", c.Name,
+		", Url:", c.Url,
 		", User:", utils.MaskUsername(c.User),
 		", LogLevel:", c.LogLevel,
+		", DryRun:", c.DryRun,
 		", BudgetPercent:", c.BudgetPercent,
 		", FuelPrice:", c.FuelPrice,
+		", PriceStrategy:", c.PriceStrategy,
+		", PriceHistory:", c.PriceHistory,
+		", FuelTiers:", c.FuelTiers,
 		", RepairLounges:", c.RepairLounges,
 		", BuyCateringIfMissing:", c.BuyCateringIfMissing,
 		", CateringDurationHours:", c.CateringDurationHours,
 		", CateringAmountOption:", c.CateringAmountOption,
 		", HubsMaintenanceLimit:", c.HubsMaintenanceLimit,
 		", FuelCriticalPercent:", c.FuelCriticalPercent,
+		", FuelReserveHours:", c.FuelReserveHours,
+		", FuelTypes:", c.FuelTypes,
 		", AircraftWearPercent:", c.AircraftWearPercent,
 		", AircraftMaxHoursToCheck:", c.AircraftMaxHoursToCheck,
 		", AircraftModifyLimit:", c.AircraftModifyLimit,
 		", CronSchedule:", c.CronSchedule,
+		", Schedules:", c.Schedules,
 		", Services:", c.Services,
 		", TimeoutSeconds:", c.TimeoutSeconds,
+		", ShutdownGraceSeconds:", c.ShutdownGraceSeconds,
+		", Resilience:", c.Resilience,
 		", ChromeHeadless:", c.ChromeHeadless,
 		", ChromeDebug:", c.ChromeDebug,
+		", ChromePersistent:", c.ChromePersistent,
 		", PrometheusAddress:", c.PrometheusAddress,
+		", LedgerFile:", c.LedgerFile,
+		", ArtifactsDir:", c.ArtifactsDir,
+		", ArtifactsKeep:", c.ArtifactsKeep,
+		", SelectorsFile:", c.SelectorsFile,
 		"}")
 }
 
+// ServiceSchedules groups the configured services by their cron schedules.
+// Services without an entry in "schedules" use the common "cron_schedule".
+// The configured order of services is kept inside every group.
+func (c *Config) ServiceSchedules() map[string][]string {
+	schedules := make(map[string][]string)
+
+	for _, serviceName := range c.Services {
+		spec, ok := c.Schedules[serviceName]
+		if !ok || spec == "" {
+			spec = c.CronSchedule
+		}
+
+		schedules[spec] = append(schedules[spec], serviceName)
+	}
+
+	for serviceName := range c.Schedules {
+		if !slices.Contains(c.Services, serviceName) {
+			slog.Warn("schedule is set for the service which is not enabled", "service", serviceName)
+		}
+	}
+
+	return schedules
+}
+
+// ForceDryRun enables the dry run mode regardless of the "dry_run" option in the configuration file.
+func (c *Config) ForceDryRun() {
+	c.forceDryRun = true
+	c.DryRun = true
+}
+
 // safeStorePassword converts password string into array of runes
 // and clears the original string to reduce the risk of password leakage in memory.
 func (c *Config) safeStorePassword() {
@@ -108,48 +298,83 @@ func (c *Config) GetPassword() string {
 // ReloadConfigIfChanged reloads the configuration from the YAML file
 // if it has changed since the last load.
 // It returns true if the configuration was reloaded, false otherwise.
+// The configuration is updated in place, so it mustn't be read concurrently, see Reload.
 func (c *Config) ReloadConfigIfChanged() (bool, error) {
-	slog.Debug("reloading config file", "file", c.confFilePath)
+	reloaded, err := c.reload()
+	if err != nil || reloaded == nil {
+		return false, err
+	}
 
-	var err error
+	*c = *reloaded
 
-	newChecksum, err := getFileChecksum(c.confFilePath)
+	return true, nil
+}
+
+// reload loads the new instance of the configuration from the YAML file
+// if it has changed since the last load. It returns nil if the configuration is unchanged.
+// The current configuration isn't modified.
+func (c *Config) reload() (*Config, error) {
+	slog.Debug("reloading config file", "file", c.confFilePath)
+
+	newChecksum, err := c.checksum()
 
 	if err != nil {
 		slog.Debug("error calculating config file checksum", "error", err)
 
-		return false, err
+		return nil, err
 	}
 
 	if newChecksum == c.configChecksum {
 		slog.Debug("config file unchanged, no reload needed")
 
-		return false, nil
+		return nil, nil
 	}
 
 	slog.Debug("config file changed, reloading", "old_checksum", c.configChecksum, "new_checksum", newChecksum)
 
-	// save previous config before reload
-	prevConfig := *c
+	// the removed account keeps running with the previous configuration until the restart
+	if c.account != "" {
+		accountNames, err := c.listAccountNames()
+		if err != nil {
+			return nil, err
+		}
 
-	// load configuration file
-	if err = c.loadConfig(); err != nil {
-		// restore previous config in case of error
-		*c = prevConfig
+		if !slices.Contains(accountNames, c.account) {
+			slog.Debug("account is removed from the accounts list, previous config is kept", "account", c.account)
 
-		slog.Debug("error reloading config, previous config has been restored", "error", err)
+			kept := *c
+			kept.accountNames = accountNames
+			kept.configChecksum = newChecksum
 
-		return false, err
+			return &kept, nil
+		}
+	}
+
+	reloaded := &Config{
+		PromslogConfig: c.PromslogConfig,
+		confFilePath:   c.confFilePath,
+		forceDryRun:    c.forceDryRun,
+		flagOverrides:  c.flagOverrides,
+		account:        c.account,
+	}
+
+	// load configuration file, the previous config stays in use in case of error
+	if err = reloaded.loadConfig(); err != nil {
+		slog.Debug("error reloading config, previous config is kept", "error", err)
+
+		return nil, err
 	}
 
 	// set log level from config
-	c.PromslogConfig.Level.Set(c.LogLevel)
-	// update stored checksum
-	c.configChecksum = newChecksum
+	reloaded.PromslogConfig.Level.Set(reloaded.LogLevel)
+	// update stored checksum, the reloaded config could refer to other secret files
+	if reloaded.configChecksum, err = reloaded.checksum(); err != nil {
+		reloaded.configChecksum = newChecksum
+	}
 
-	slog.Debug("config reloaded", "config", c)
+	slog.Debug("config reloaded", "config", reloaded)
 
-	return true, nil
+	return reloaded, nil
 }
 
 // loadConfig loads the configuration from the YAML file
@@ -159,6 +384,9 @@ func (c *Config) loadConfig() error {
 	// set default values
 	defaults.Set(c)
 
+	c.secretPaths = nil
+	secrets := c.secretValues()
+
 	// load YAML configuration
 	if err := loadYaml(c.confFilePath, c); err != nil {
 		slog.Debug("error loading config file", "error", err)
@@ -166,6 +394,59 @@ func (c *Config) loadConfig() error {
 		return err
 	}
 
+	if err := c.loadSecretFiles(secrets); err != nil {
+		slog.Debug("error loading secret files", "error", err)
+
+		return err
+	}
+
+	// added or removed accounts are reported by the reload, errors of the list are returned by Accounts
+	c.accountNames, _ = c.listAccountNames()
+
+	// options of the account override the top-level ones
+	if c.account != "" {
+		secrets = c.secretValues()
+
+		if err := c.loadAccount(); err != nil {
+			slog.Debug("error loading account config", "account", c.account, "error", err)
+
+			return err
+		}
+
+		if err := c.loadSecretFiles(secrets); err != nil {
+			slog.Debug("error loading secret files", "account", c.account, "error", err)
+
+			return err
+		}
+	}
+
+	// environment variables and CLI flags override the configuration file, including the secret files
+	secrets = c.secretValues()
+	overrideErrs := c.applyOverrides()
+
+	if err := c.loadSecretFiles(secrets); err != nil {
+		slog.Debug("error loading secret files", "error", err)
+
+		return err
+	}
+
+	if err := c.loadSelectorOverrides(); err != nil {
+		slog.Debug("error loading selectors file", "file", c.SelectorsFile, "error", err)
+
+		return err
+	}
+
+	if err := c.validate(overrideErrs); err != nil {
+		slog.Debug("invalid configuration", "error", err)
+
+		return err
+	}
+
+	// dry run mode from CLI has priority over the configuration file
+	if c.forceDryRun {
+		c.DryRun = true
+	}
+
 	// securely store password
 	c.safeStorePassword()
 
@@ -177,6 +458,13 @@ func (c *Config) loadConfig() error {
 // New creates a new Config instance and loading the configuration
 // from the specified YAML file.
 func New(filePath string) (*Config, error) {
+	return NewWithOverrides(filePath, nil)
+}
+
+// NewWithOverrides creates a new Config instance and loading the configuration
+// from the specified YAML file. The options keyed by their paths (e.g. "budget_percent.fuel")
+// override the configuration file and the "AMBOT_*" environment variables.
+func NewWithOverrides(filePath string, overrides map[string]string) (*Config, error) {
 	slog.Debug("creating new Config instance", "file", filePath)
 
 	var err error
@@ -184,7 +472,15 @@ func New(filePath string) (*Config, error) {
 	// create new Config instance
 	c := new(Config)
 	c.confFilePath = filePath
-	c.configChecksum, err = getFileChecksum(filePath)
+	c.flagOverrides = overrides
+
+	// load configuration
+	if err := c.loadConfig(); err != nil {
+		return nil, err
+	}
+
+	// the checksum includes the secret files referred by the configuration
+	c.configChecksum, err = c.checksum()
 
 	if err != nil {
 		slog.Debug("error calculating config file checksum", "error", err)
@@ -192,35 +488,253 @@ func New(filePath string) (*Config, error) {
 		return nil, err
 	}
 
-	// load configuration
-	if err := c.loadConfig(); err != nil {
+	slog.Debug("config loaded", "config", c)
+
+	return c, nil
+}
+
+// Accounts returns the configurations of all accounts from the "accounts" list of the configuration file.
+// Every account inherits the top-level options and overrides them with its own ones.
+// The configuration itself is returned as the single account if the list is empty.
+func (c *Config) Accounts() ([]*Config, error) {
+	entries, err := c.accountEntries()
+	if err != nil {
 		return nil, err
 	}
 
-	slog.Debug("config loaded", "config", c)
+	if len(entries) == 0 {
+		return []*Config{c}, nil
+	}
 
-	return c, nil
+	accounts := make([]*Config, 0, len(entries))
+	remoteUrls := make(map[string]string)
+
+	for _, entry := range entries {
+		name, err := accountName(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validateAccountName(name); err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(accounts, func(a *Config) bool { return a.Name == name }) {
+			return nil, fmt.Errorf("duplicate account name %q in the accounts list", name)
+		}
+
+		account := &Config{
+			PromslogConfig: c.PromslogConfig,
+			confFilePath:   c.confFilePath,
+			forceDryRun:    c.forceDryRun,
+			flagOverrides:  c.flagOverrides,
+			account:        name,
+		}
+
+		if err := account.loadConfig(); err != nil {
+			return nil, err
+		}
+
+		// the account could refer to its own secret files
+		if account.configChecksum, err = account.checksum(); err != nil {
+			return nil, err
+		}
+
+		// the remote browser keeps its cookies, so the accounts would share the session
+		if other, ok := remoteUrls[account.ChromeRemoteUrl]; ok && account.ChromeRemoteUrl != "" {
+			slog.Warn("accounts share the same remote browser", "account", account.Name, "other_account", other)
+		}
+
+		remoteUrls[account.ChromeRemoteUrl] = account.Name
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// accountEntries returns the raw entries of the "accounts" list.
+func (c *Config) accountEntries() ([]yaml.Node, error) {
+	var file accountsFile
+
+	if err := loadYaml(c.confFilePath, &file); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range file.Accounts {
+		if entry.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("line %d: the entry of the accounts list must be a mapping", entry.Line)
+		}
+	}
+
+	return file.Accounts, nil
+}
+
+// listAccountNames returns the sorted names of the entries in the "accounts" list.
+func (c *Config) listAccountNames() ([]string, error) {
+	entries, err := c.accountEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		name, err := accountName(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// loadAccount loads the options of the account from the "accounts" list over the top-level options.
+func (c *Config) loadAccount() error {
+	entries, err := c.accountEntries()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name, err := accountName(entry)
+		if err != nil {
+			return err
+		}
+
+		if name != c.account {
+			continue
+		}
+
+		if err := entry.Decode(c); err != nil {
+			return fmt.Errorf("account %q: %w", c.account, err)
+		}
+
+		return nil
+	}
+
+	// new accounts are started only on the application start, so the removed one can't be replaced
+	return fmt.Errorf("account %q is not found in the accounts list", c.account)
+}
+
+// accountName returns the name of the entry in the "accounts" list.
+func accountName(entry yaml.Node) (string, error) {
+	var identity accountEntry
+
+	if err := entry.Decode(&identity); err != nil {
+		return "", err
+	}
+
+	return identity.Name, nil
+}
+
+// validateAccountName checks that the account name can be used in file paths and metric labels.
+func validateAccountName(name string) error {
+	if name == "" {
+		return fmt.Errorf("the entry of the accounts list has no name")
+	}
+
+	if !accountNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid account name %q: only letters, digits, '_', '.' and '-' are allowed, it can't start with '.'", name)
+	}
+
+	return nil
+}
+
+// SelectorOverrides returns the CSS selectors from the "selectors_file" keyed by their names.
+func (c *Config) SelectorOverrides() map[string]string {
+	return c.selectorOverrides
+}
+
+// loadSelectorOverrides loads the CSS selectors from the "selectors_file".
+// The file is YAML or JSON with the selectors keyed by their names, e.g. "TEXT_FUEL_FUEL_PRICE".
+func (c *Config) loadSelectorOverrides() error {
+	c.selectorOverrides = nil
+
+	if c.SelectorsFile == "" {
+		return nil
+	}
+
+	overrides := make(map[string]string)
+
+	if err := loadYaml(c.SelectorsFile, &overrides); err != nil {
+		return fmt.Errorf("selectors file %q: %w", c.SelectorsFile, err)
+	}
+
+	for name, sel := range overrides {
+		if sel == "" {
+			return fmt.Errorf("selectors file %q: selector %s is empty", c.SelectorsFile, name)
+		}
+	}
+
+	c.selectorOverrides = overrides
+
+	return nil
 }
 
 // loadYaml reads a YAML file from the specified path
 // and unmarshals its content into the provided output structure.
 func loadYaml(filePath string, out any) error {
+	node, err := readYaml(filePath)
+	if err != nil {
+		return err
+	}
+
+	// the empty file
+	if node.Kind == 0 {
+		return nil
+	}
+
+	return node.Decode(out)
+}
+
+// readYaml reads a YAML file from the specified path and expands the environment variables in its values.
+func readYaml(filePath string) (*yaml.Node, error) {
 	var err error
 	var f []byte
 
 	slog.Debug("read file", "file", filePath)
 
 	if f, err = os.ReadFile(filePath); err != nil {
-		return err
+		return nil, err
 	}
 
 	slog.Debug("load file as yaml", "file", filePath)
 
-	if err := yaml.Unmarshal(f, out); err != nil {
-		return err
+	var node yaml.Node
+
+	if err := yaml.Unmarshal(f, &node); err != nil {
+		return nil, err
+	}
+
+	if err := expandEnv(&node); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+// checksum computes the checksum of the configuration file and the files referred by it.
+func (c *Config) checksum() (string, error) {
+	checksum, err := getFileChecksum(c.confFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, filePath := range c.WatchedFiles()[1:] {
+		fileChecksum, err := getFileChecksum(filePath)
+		if err != nil {
+			return "", err
+		}
+
+		checksum += fileChecksum
 	}
 
-	return err
+	return checksum, nil
 }
 
 // getFileChecksum computes and returns the SHA-256 checksum of the specified file.
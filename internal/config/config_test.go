@@ -0,0 +1,643 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/common/promslog"
+)
+
+// writeConfig writes the configuration file into the temporary directory and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(confPath, []byte(content), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	return confPath
+}
+
+func TestAccounts(t *testing.T) {
+	confPath := writeConfig(t, `
+username: "top@example.com"
+password: "top"
+services: ["buy_fuel", "depart"]
+budget_percent:
+  fuel: 50
+accounts:
+  - name: "first"
+    username: "first@example.com"
+    password: "first"
+  - name: "second"
+    username: "second@example.com"
+    password: "second"
+    services: ["depart"]
+    budget_percent:
+      marketing: 10
+`)
+
+	conf, err := New(confPath)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	accounts, err := conf.Accounts()
+	if err != nil {
+		t.Fatalf("Accounts() returned error: %v", err)
+	}
+
+	if len(accounts) != 2 {
+		t.Fatalf(`Accounts() returned '%d' accounts, expected '%d'`, len(accounts), 2)
+	}
+
+	testCases := map[string]struct {
+		account          *Config
+		expectedName     string
+		expectedUser     string
+		expectedPassword string
+		expectedServices []string
+		expectedBudget   BudgetType
+	}{
+		"test01": {accounts[0], "first", "first@example.com", "first", []string{"buy_fuel", "depart"}, BudgetType{50, 70, 50}},
+		"test02": {accounts[1], "second", "second@example.com", "second", []string{"depart"}, BudgetType{50, 10, 50}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			a := testData.account
+
+			if a.Name != testData.expectedName || a.User != testData.expectedUser || a.GetPassword() != testData.expectedPassword {
+				t.Errorf(`account '%s' (%s), expected '%s' (%s)`, a.Name, a.User, testData.expectedName, testData.expectedUser)
+			}
+
+			if !slices.Equal(a.Services, testData.expectedServices) {
+				t.Errorf(`account services '%+v', expected '%+v'`, a.Services, testData.expectedServices)
+			}
+
+			if a.BudgetPercent != testData.expectedBudget {
+				t.Errorf(`account budget '%+v', expected '%+v'`, a.BudgetPercent, testData.expectedBudget)
+			}
+		})
+	}
+}
+
+func TestSingleAccount(t *testing.T) {
+	conf, err := New(writeConfig(t, `username: "top@example.com"`))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	accounts, err := conf.Accounts()
+	if err != nil {
+		t.Fatalf("Accounts() returned error: %v", err)
+	}
+
+	if len(accounts) != 1 || accounts[0] != conf || conf.Name != DEFAULT_ACCOUNT_NAME {
+		t.Errorf(`Accounts() returned '%+v', expected the configuration itself`, accounts)
+	}
+}
+
+func TestAccountsErrors(t *testing.T) {
+	testCases := map[string]struct {
+		accounts string
+	}{
+		"test01": {"accounts:\n  - username: \"first@example.com\"\n"},
+		"test02": {"accounts:\n  - name: \"first\"\n  - name: \"first\"\n"},
+		"test03": {"accounts:\n  - name: \"../first\"\n"},
+		"test04": {"accounts:\n  - \"first\"\n"},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			conf, err := New(writeConfig(t, testData.accounts))
+			if err != nil {
+				t.Fatalf("New() returned error: %v", err)
+			}
+
+			if _, err := conf.Accounts(); err == nil {
+				t.Errorf(`Accounts() of '%s' didn't return error`, testData.accounts)
+			}
+		})
+	}
+}
+
+func TestReloadAccount(t *testing.T) {
+	confPath := writeConfig(t, "accounts:\n  - name: \"first\"\n    dry_run: false\n")
+
+	conf, err := New(confPath)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	accounts, err := conf.Accounts()
+	if err != nil {
+		t.Fatalf("Accounts() returned error: %v", err)
+	}
+
+	account := accounts[0]
+	account.PromslogConfig = &promslog.Config{Level: promslog.NewLevel()}
+
+	if err := os.WriteFile(confPath, []byte("accounts:\n  - name: \"first\"\n    dry_run: true\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	if _, err := account.ReloadConfigIfChanged(); err != nil {
+		t.Fatalf("ReloadConfigIfChanged() returned error: %v", err)
+	}
+
+	if !account.DryRun {
+		t.Errorf("options of the account haven't been reloaded")
+	}
+
+	// the removed account keeps the previous configuration until the restart
+	if err := os.WriteFile(confPath, []byte("accounts:\n  - name: \"second\"\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	reloaded, changes, err := account.Reload()
+	if err != nil {
+		t.Fatalf("Reload() of the removed account returned error: %v", err)
+	}
+
+	if reloaded == nil || reloaded.Name != "first" || !reloaded.DryRun {
+		t.Errorf("removed account has been reloaded")
+	}
+
+	if expected := []Change{{"accounts", true}}; !slices.Equal(changes, expected) {
+		t.Errorf(`changes '%v', expected '%v'`, changes, expected)
+	}
+
+	// the change is reported once
+	if reloaded, _, err := reloaded.Reload(); reloaded != nil || err != nil {
+		t.Errorf(`Reload() of the unchanged config returned '%v', '%v'`, reloaded, err)
+	}
+}
+
+func TestSelectorsFile(t *testing.T) {
+	testCases := map[string]struct {
+		selectors string
+		expected  map[string]string
+		isErr     bool
+	}{
+		"test01": {"TEXT_FUEL_FUEL_PRICE: \"span#price\"\n", map[string]string{"TEXT_FUEL_FUEL_PRICE": "span#price"}, false},
+		"test02": {`{"TEXT_FUEL_FUEL_PRICE": "span#price"}`, map[string]string{"TEXT_FUEL_FUEL_PRICE": "span#price"}, false},
+		"test03": {"TEXT_FUEL_FUEL_PRICE: \"\"\n", nil, true},
+		"test04": {"- \"span#price\"\n", nil, true},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			selectorsPath := filepath.Join(t.TempDir(), "selectors.yaml")
+
+			if err := os.WriteFile(selectorsPath, []byte(testData.selectors), 0600); err != nil {
+				t.Fatalf("os.WriteFile() returned error: %v", err)
+			}
+
+			conf, err := New(writeConfig(t, "selectors_file: \""+selectorsPath+"\"\n"))
+			if (err != nil) != testData.isErr {
+				t.Fatalf(`New() returned error '%v', expected error '%v'`, err, testData.isErr)
+			}
+
+			if err == nil && !maps.Equal(conf.SelectorOverrides(), testData.expected) {
+				t.Errorf(`selector overrides '%+v', expected '%+v'`, conf.SelectorOverrides(), testData.expected)
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("AM4BOT_TEST_PASSWORD", "secret")
+	t.Setenv("AM4BOT_TEST_TIMEOUT", "300")
+
+	testCases := map[string]struct {
+		content          string
+		expectedPassword string
+		expectedTimeout  int
+		isErr            bool
+	}{
+		"test01": {"password: \"${AM4BOT_TEST_PASSWORD}\"\n", "secret", 180, false},
+		"test02": {"password: pre-${AM4BOT_TEST_PASSWORD}-post\n", "pre-secret-post", 180, false},
+		"test03": {"timeout_seconds: ${AM4BOT_TEST_TIMEOUT}\n", "", 300, false},
+		"test04": {"password: ${AM4BOT_TEST_UNSET:-fallback}\n", "fallback", 180, false},
+		"test05": {"password: ${AM4BOT_TEST_UNSET}\n", "", 180, true},
+		"test06": {"password: \"$${AM4BOT_TEST_PASSWORD}\"\n", "${AM4BOT_TEST_PASSWORD}", 180, false},
+		"test07": {"password: \"pa$$word\"\n", "pa$$word", 180, false},
+		"test08": {"timeout_seconds: \"${AM4BOT_TEST_TIMEOUT}\"\n", "", 0, true},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			conf, err := New(writeConfig(t, testData.content))
+			if (err != nil) != testData.isErr {
+				t.Fatalf(`New() returned error '%v', expected error '%v'`, err, testData.isErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if conf.GetPassword() != testData.expectedPassword || conf.TimeoutSeconds != testData.expectedTimeout {
+				t.Errorf(`password '%s' and timeout '%d', expected '%s' and '%d'`,
+					conf.GetPassword(), conf.TimeoutSeconds, testData.expectedPassword, testData.expectedTimeout)
+			}
+		})
+	}
+}
+
+func TestSecretFiles(t *testing.T) {
+	secretsDir := t.TempDir()
+	secrets := map[string]string{
+		"password":        "top\n",
+		"second_password": "second\r\n",
+		"token":           "token",
+	}
+
+	for name, content := range secrets {
+		if err := os.WriteFile(filepath.Join(secretsDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("os.WriteFile() returned error: %v", err)
+		}
+	}
+
+	t.Setenv("AM4BOT_TEST_SECRETS", secretsDir)
+
+	confPath := writeConfig(t, `
+password: "ignored"
+password_file: "${AM4BOT_TEST_SECRETS}/password"
+api_token_file: "${AM4BOT_TEST_SECRETS}/token"
+accounts:
+  - name: "first"
+  - name: "second"
+    password_file: "${AM4BOT_TEST_SECRETS}/second_password"
+  - name: "third"
+    password: "third"
+`)
+
+	conf, err := New(confPath)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	accounts, err := conf.Accounts()
+	if err != nil {
+		t.Fatalf("Accounts() returned error: %v", err)
+	}
+
+	testCases := map[string]struct {
+		account              *Config
+		expectedPassword     string
+		expectedPasswordFile string
+	}{
+		"test01": {conf, "top", filepath.Join(secretsDir, "password")},
+		"test02": {accounts[0], "top", filepath.Join(secretsDir, "password")},
+		"test03": {accounts[1], "second", filepath.Join(secretsDir, "second_password")},
+		"test04": {accounts[2], "third", ""},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			if testData.account.GetPassword() != testData.expectedPassword {
+				t.Errorf(`password '%s', expected '%s'`, testData.account.GetPassword(), testData.expectedPassword)
+			}
+
+			// the path of the file is kept, only the secret value is masked
+			effective, err := testData.account.Effective()
+			if err != nil {
+				t.Fatalf("Effective() returned error: %v", err)
+			}
+
+			if testData.account.PasswordFile != testData.expectedPasswordFile || effective["password"] != MASKED_VALUE {
+				t.Errorf(`password file '%s' and effective password '%v', expected '%s' and '%s'`,
+					testData.account.PasswordFile, effective["password"], testData.expectedPasswordFile, MASKED_VALUE)
+			}
+
+			if testData.account.ApiToken != "token" {
+				t.Errorf(`api token '%s', expected '%s'`, testData.account.ApiToken, "token")
+			}
+		})
+	}
+
+	if _, err := New(writeConfig(t, "password_file: \""+filepath.Join(secretsDir, "missing")+"\"\n")); err == nil {
+		t.Errorf("New() with the missing password file returned no error")
+	}
+}
+
+func TestReloadSecretFile(t *testing.T) {
+	passwordPath := filepath.Join(t.TempDir(), "password")
+
+	if err := os.WriteFile(passwordPath, []byte("old\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	conf, err := New(writeConfig(t, "password_file: \""+passwordPath+"\"\n"))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	conf.PromslogConfig = &promslog.Config{Level: promslog.NewLevel()}
+
+	if reloaded, err := conf.ReloadConfigIfChanged(); err != nil || reloaded {
+		t.Errorf(`ReloadConfigIfChanged() of the unchanged config returned '%v', '%v'`, reloaded, err)
+	}
+
+	if err := os.WriteFile(passwordPath, []byte("new\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	if reloaded, err := conf.ReloadConfigIfChanged(); err != nil || !reloaded {
+		t.Errorf(`ReloadConfigIfChanged() after the change of the secret file returned '%v', '%v'`, reloaded, err)
+	}
+
+	if conf.GetPassword() != "new" {
+		t.Errorf(`password '%s', expected '%s'`, conf.GetPassword(), "new")
+	}
+
+	if reloaded, err := conf.ReloadConfigIfChanged(); err != nil || reloaded {
+		t.Errorf(`ReloadConfigIfChanged() after the reload returned '%v', '%v'`, reloaded, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		content        string
+		expectedFields []string
+	}{
+		"test01": {"catering_duration_hours: \"48\"\naircraft_wear_percent: \"90\"\n", nil},
+		"test02": {"catering_duration_hours: \"7\"\n", []string{"catering_duration_hours"}},
+		"test03": {"aircraft_wear_percent: \"85\"\ncatering_amount_option: \"1\"\n", []string{"aircraft_wear_percent", "catering_amount_option"}},
+		"test04": {"budget_percent:\n  fuel: 101\n  marketing: -1\n", []string{"budget_percent.fuel", "budget_percent.marketing"}},
+		"test05": {"min_route_range_km: 9000\nmax_route_range_km: 6500\n", []string{"min_route_range_km"}},
+		"test06": {"usernme: \"user@example.com\"\nbudget_percent:\n  fual: 50\n", []string{"budget_percent.fual", "usernme"}},
+		"test07": {"accounts:\n  - name: \"first\"\n    pasword: \"secret\"\n", []string{"accounts[0].pasword"}},
+		"test08": {"cron_schedule: \"*/5 * *\"\nschedules:\n  depart: \"@every 1m\"\n  buy_fuel: \"61 * * * *\"\n", []string{"cron_schedule", "schedules.buy_fuel"}},
+		"test09": {"url: \"airlinemanager.com\"\nlog_level: \"verbose\"\ntimeout_seconds: 0\n", []string{"log_level", "timeout_seconds", "url"}},
+		"test10": {"resilience:\n  backoff_initial_seconds: 600\n  backoff_max_seconds: 60\n", []string{"resilience.backoff_max_seconds"}},
+		"test11": {"price_strategy: \"median\"\nprice_history:\n  percentile: 120\n  days: 0\n", []string{"price_history.days", "price_history.percentile", "price_strategy"}},
+		"test12": {"fuel_tiers:\n  fuel:\n    - max_price: 400\n      fill_percent: 100\n    - max_price: 600\n      fill_pct: 60\n  co2:\n    - max_price: 150\n      fill_percent: 120\n",
+			[]string{"fuel_tiers.co2[0].fill_percent", "fuel_tiers.fuel[1].fill_pct", "fuel_tiers.fuel[1].fill_percent"}},
+		"test13": {"fuel_types:\n  fuel:\n    critical_percent: 10\n  co2:\n    budget_percent: 120\n    max_price: -1\n",
+			[]string{"fuel_types.co2.budget_percent", "fuel_types.co2.max_price"}},
+		"test14": {"accounts:\n  - name: \"first\"\n    selectors_file: \"selectors.yaml\"\n", []string{"accounts[0].selectors_file"}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			_, err := New(writeConfig(t, testData.content))
+
+			var fields []string
+
+			if err != nil {
+				validationErr, ok := err.(*ValidationError)
+				if !ok {
+					t.Fatalf(`New() returned error '%v', expected the validation error`, err)
+				}
+
+				for _, fieldErr := range validationErr.Errors {
+					fields = append(fields, fieldErr.Field)
+				}
+			}
+
+			slices.Sort(fields)
+
+			if !slices.Equal(fields, testData.expectedFields) {
+				t.Errorf(`invalid fields '%+v', expected '%+v'`, fields, testData.expectedFields)
+			}
+		})
+	}
+}
+
+func TestValidateExample(t *testing.T) {
+	conf, err := New(filepath.Join("..", "..", "config_example.yaml"))
+	if err != nil {
+		t.Fatalf("New() returned error for the example configuration: %v", err)
+	}
+
+	if _, err := conf.Accounts(); err != nil {
+		t.Errorf("Accounts() returned error for the example configuration: %v", err)
+	}
+}
+
+func TestReloadInvalid(t *testing.T) {
+	confPath := writeConfig(t, "aircraft_wear_percent: \"70\"\n")
+
+	conf, err := New(confPath)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	conf.PromslogConfig = &promslog.Config{Level: promslog.NewLevel()}
+
+	if err := os.WriteFile(confPath, []byte("aircraft_wear_percent: \"75\"\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	if reloaded, err := conf.ReloadConfigIfChanged(); err == nil || reloaded {
+		t.Errorf(`ReloadConfigIfChanged() of the invalid config returned '%v', '%v'`, reloaded, err)
+	}
+
+	if conf.AircraftWearPercent != "70" {
+		t.Errorf(`aircraft_wear_percent '%s' after the rejected reload, expected '%s'`, conf.AircraftWearPercent, "70")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	confPath := writeConfig(t, `
+password: "file"
+budget_percent:
+  fuel: 50
+  marketing: 60
+services: ["depart"]
+accounts:
+  - name: "first"
+    budget_percent:
+      marketing: 10
+`)
+
+	t.Setenv("AMBOT_BUDGET_PERCENT_FUEL", "40")
+	t.Setenv("AMBOT_BUDGET_PERCENT_MARKETING", "30")
+	t.Setenv("AMBOT_SERVICES", "buy_fuel, depart")
+	t.Setenv("AMBOT_DRY_RUN", "true")
+	t.Setenv("AMBOT_NAME", "renamed")
+
+	conf, err := NewWithOverrides(confPath, map[string]string{
+		"budget_percent.fuel": "20",
+		"password":            "flag",
+		"cron_schedule":       "*/10 * * * *",
+	})
+	if err != nil {
+		t.Fatalf("NewWithOverrides() returned error: %v", err)
+	}
+
+	accounts, err := conf.Accounts()
+	if err != nil {
+		t.Fatalf("Accounts() returned error: %v", err)
+	}
+
+	testCases := map[string]struct {
+		account      *Config
+		expectedName string
+	}{
+		"test01": {conf, "renamed"},
+		"test02": {accounts[0], "first"},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			c := testData.account
+
+			// flag > env > file > default
+			if c.BudgetPercent.Fuel != 20 || c.BudgetPercent.Marketing != 30 || c.BudgetPercent.Maintenance != 50 {
+				t.Errorf(`budget percent '%+v', expected fuel 20, marketing 30, maintenance 50`, c.BudgetPercent)
+			}
+
+			if c.Name != testData.expectedName || c.GetPassword() != "flag" || !c.DryRun || c.CronSchedule != "*/10 * * * *" {
+				t.Errorf(`overridden options '%s', '%s', '%v', '%s' aren't applied`, c.Name, c.GetPassword(), c.DryRun, c.CronSchedule)
+			}
+
+			if !slices.Equal(c.Services, []string{"buy_fuel", "depart"}) {
+				t.Errorf(`services '%+v', expected '%+v'`, c.Services, []string{"buy_fuel", "depart"})
+			}
+		})
+	}
+
+	testErrors := map[string]struct {
+		env           string
+		flags         map[string]string
+		expectedField string
+	}{
+		"test01": {"", map[string]string{"budget_percent.fual": "20"}, "--config.set budget_percent.fual"},
+		"test02": {"", map[string]string{"timeout_seconds": "fast"}, "--config.set timeout_seconds"},
+		"test03": {"fast", nil, "AMBOT_TIMEOUT_SECONDS"},
+	}
+
+	for testName, testData := range testErrors {
+		t.Run(testName, func(t *testing.T) {
+			if testData.env != "" {
+				t.Setenv("AMBOT_TIMEOUT_SECONDS", testData.env)
+			}
+
+			_, err := NewWithOverrides(writeConfig(t, ""), testData.flags)
+
+			validationErr, ok := err.(*ValidationError)
+			if !ok || !slices.ContainsFunc(validationErr.Errors, func(e FieldError) bool { return e.Field == testData.expectedField }) {
+				t.Errorf(`NewWithOverrides() returned error '%v', expected the error of '%s'`, err, testData.expectedField)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	envNames := make(map[string]string)
+
+	for _, option := range Options() {
+		if other, ok := envNames[envName(option)]; ok {
+			t.Errorf(`options '%s' and '%s' have the same environment variable '%s'`, option, other, envName(option))
+		}
+
+		envNames[envName(option)] = option
+	}
+
+	for _, option := range []string{"password", "budget_percent.fuel", "resilience.failure_threshold", "schedules"} {
+		if !slices.Contains(Options(), option) {
+			t.Errorf(`Options() doesn't contain '%s'`, option)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	testCases := map[string]struct {
+		content         string
+		changedContent  string
+		expectedChanges []Change
+	}{
+		"test01": {"cron_schedule: \"*/5 * * * *\"\n", "cron_schedule: \"*/5 * * * *\"\n", nil},
+		"test02": {"cron_schedule: \"*/5 * * * *\"\n", "cron_schedule: \"*/10 * * * *\"\nbudget_percent:\n  fuel: 50\n",
+			[]Change{{"budget_percent.fuel", false}, {"cron_schedule", false}}},
+		"test03": {"name: \"first\"\n", "name: \"second\"\nschedules:\n  depart: \"@every 1m\"\n",
+			[]Change{{"name", true}, {"schedules.depart", false}}},
+		"test04": {"prometheus_address: \":9150\"\n", "prometheus_address: \":9151\"\n", []Change{{"prometheus_address", false}}},
+		// new accounts are started only after the restart
+		"test05": {"accounts:\n  - name: \"first\"\n", "accounts:\n  - name: \"first\"\n  - name: \"second\"\n", []Change{{"accounts", true}}},
+		// the order of the accounts doesn't matter
+		"test06": {"accounts:\n  - name: \"first\"\n  - name: \"second\"\n", "accounts:\n  - name: \"second\"\n  - name: \"first\"\n", nil},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			confPath := writeConfig(t, testData.content)
+
+			conf, err := New(confPath)
+			if err != nil {
+				t.Fatalf("New() returned error: %v", err)
+			}
+
+			conf.PromslogConfig = &promslog.Config{Level: promslog.NewLevel()}
+
+			if err := os.WriteFile(confPath, []byte(testData.changedContent), 0600); err != nil {
+				t.Fatalf("os.WriteFile() returned error: %v", err)
+			}
+
+			previous, err := conf.values()
+			if err != nil {
+				t.Fatalf("values() returned error: %v", err)
+			}
+
+			reloaded, changes, err := conf.Reload()
+			if err != nil {
+				t.Fatalf("Reload() returned error: %v", err)
+			}
+
+			if !slices.Equal(changes, testData.expectedChanges) {
+				t.Errorf(`changes '%v', expected '%v'`, changes, testData.expectedChanges)
+			}
+
+			if (reloaded != nil) != (testData.content != testData.changedContent) {
+				t.Errorf(`reloaded config '%v', expected it only for the changed file`, reloaded)
+			}
+
+			// the current configuration could be read concurrently, so it's never modified
+			if current, _ := conf.values(); !reflect.DeepEqual(current, previous) {
+				t.Errorf(`current config has been modified by Reload()`)
+			}
+		})
+	}
+}
+
+func TestFuelTypeSettings(t *testing.T) {
+	t.Setenv("AMBOT_FUEL_TYPES_FUEL_BUDGET_PERCENT", "30")
+
+	conf, err := New(writeConfig(t, "fuel_critical_percent: 15\nfuel_types:\n  co2:\n    critical_percent: 40\n"))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	testCases := map[string]struct {
+		fuelType               string
+		expectedCritical       float64
+		expectedBudget         float64
+		expectedBudgetOfItsOwn bool
+	}{
+		"test01": {"fuel", 15, 30, true},
+		"test02": {"co2", 40, 70, false},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			if critical := conf.FuelTypeCriticalPercent(testData.fuelType); critical != testData.expectedCritical {
+				t.Errorf(`FuelTypeCriticalPercent(%s) returned '%v', expected '%v'`, testData.fuelType, critical, testData.expectedCritical)
+			}
+
+			budget, ok := conf.FuelTypeBudgetPercent(testData.fuelType)
+			if budget != testData.expectedBudget || ok != testData.expectedBudgetOfItsOwn {
+				t.Errorf(`FuelTypeBudgetPercent(%s) returned '%v', '%t', expected '%v', '%t'`,
+					testData.fuelType, budget, ok, testData.expectedBudget, testData.expectedBudgetOfItsOwn)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ashokhin/am4bot/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ENV_PREFIX defines the prefix of the environment variables which override the options,
+	// e.g. "AMBOT_BUDGET_PERCENT_FUEL" overrides "budget_percent.fuel".
+	ENV_PREFIX string = "AMBOT_"
+	// MASKED_VALUE replaces the secrets in the effective configuration.
+	MASKED_VALUE string = "***"
+)
+
+// Options returns the paths of all options which could be overridden, e.g. "budget_percent.fuel".
+func Options() []string {
+	return optionPaths(reflect.TypeFor[Config](), "")
+}
+
+// optionPaths returns the paths of the options of the struct type, nested structs are expanded.
+func optionPaths(t reflect.Type, prefix string) []string {
+	var paths []string
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			paths = append(paths, optionPaths(field.Type, prefix+name+".")...)
+
+			continue
+		}
+
+		paths = append(paths, prefix+name)
+	}
+
+	return paths
+}
+
+// envName returns the name of the environment variable which overrides the option.
+func envName(option string) string {
+	return ENV_PREFIX + strings.ToUpper(strings.ReplaceAll(option, ".", "_"))
+}
+
+// applyOverrides sets the options from the "AMBOT_*" environment variables and then from the CLI flags,
+// so the flag has priority over the environment variable, and both have priority over the configuration file.
+// The "name" of the account from the "accounts" list isn't overridden.
+func (c *Config) applyOverrides() []FieldError {
+	var errs []FieldError
+
+	options := Options()
+	envOptions := make(map[string]string, len(options))
+
+	for _, option := range options {
+		envOptions[envName(option)] = option
+	}
+
+	var envNames []string
+
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+
+		if strings.HasPrefix(name, ENV_PREFIX) {
+			envNames = append(envNames, name)
+		}
+	}
+
+	slices.Sort(envNames)
+
+	for _, name := range envNames {
+		option, ok := envOptions[name]
+		if !ok {
+			slog.Warn("unknown option in the environment variable is ignored", "variable", name)
+
+			continue
+		}
+
+		if err := c.setOption(option, os.Getenv(name)); err != nil {
+			errs = append(errs, FieldError{Field: name, Message: err.Error()})
+		}
+	}
+
+	flagOptions := make([]string, 0, len(c.flagOverrides))
+
+	for option := range c.flagOverrides {
+		flagOptions = append(flagOptions, option)
+	}
+
+	slices.Sort(flagOptions)
+
+	for _, option := range flagOptions {
+		if !slices.Contains(options, option) {
+			errs = append(errs, FieldError{Field: "--config.set " + option, Message: "unknown option"})
+
+			continue
+		}
+
+		if err := c.setOption(option, c.flagOverrides[option]); err != nil {
+			errs = append(errs, FieldError{Field: "--config.set " + option, Message: err.Error()})
+		}
+	}
+
+	return errs
+}
+
+// setOption sets the value of the option by its path. Strings are set as is,
+// other values are parsed as YAML, e.g. "true" or "[buy_fuel, depart]".
+// Lists of strings could be comma-separated as well.
+func (c *Config) setOption(option string, value string) error {
+	if c.account != "" && option == "name" {
+		return nil
+	}
+
+	field := reflect.ValueOf(c).Elem()
+
+	for name := range strings.SplitSeq(option, ".") {
+		field = field.Field(yamlFieldIndex(field.Type(), name))
+	}
+
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+
+		return nil
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "["):
+		items := []string{}
+
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		field.Set(reflect.ValueOf(items))
+
+		return nil
+	}
+
+	parsed := reflect.New(field.Type())
+
+	if err := yaml.Unmarshal([]byte(value), parsed.Interface()); err != nil {
+		return fmt.Errorf("invalid value %q: %w", value, err)
+	}
+
+	field.Set(parsed.Elem())
+
+	return nil
+}
+
+// Effective returns the options of the loaded configuration keyed by their names
+// with the masked secrets: the username is partially masked, the password and the API token are replaced.
+func (c *Config) Effective() (map[string]any, error) {
+	masked := *c
+
+	masked.User = utils.MaskUsername(c.User)
+
+	if c.GetPassword() != "" {
+		masked.Password = MASKED_VALUE
+	}
+
+	if c.ApiToken != "" {
+		masked.ApiToken = MASKED_VALUE
+	}
+
+	// tokens of the remote browser services are passed in the query
+	if u, err := url.Parse(c.ChromeRemoteUrl); err == nil && u.RawQuery != "" {
+		u.RawQuery = MASKED_VALUE
+		masked.ChromeRemoteUrl = u.String()
+	}
+
+	out, err := yaml.Marshal(&masked)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := make(map[string]any)
+
+	if err := yaml.Unmarshal(out, &effective); err != nil {
+		return nil, err
+	}
+
+	return effective, nil
+}
+
+// EffectiveString returns the effective configuration as the single-line JSON for logs.
+func (c *Config) EffectiveString() string {
+	effective, err := c.Effective()
+	if err != nil {
+		return err.Error()
+	}
+
+	out, err := json.Marshal(effective)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(out)
+}
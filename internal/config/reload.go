@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// restartOptions holds the options which are applied only after the restart of the application.
+// The "accounts" option reports the added or removed entries of the accounts list.
+var restartOptions = []string{"accounts", "name"}
+
+// Change describes the changed option of the reloaded configuration.
+type Change struct {
+	Option string `json:"option"`
+	// Restart reports that the new value is applied only after the restart of the application.
+	Restart bool `json:"restart"`
+}
+
+// Reload loads the new instance of the configuration if it has changed like ReloadConfigIfChanged
+// and returns it with the options which values have been changed. The current configuration isn't modified,
+// so it can be read concurrently. Reload returns nil if the configuration is unchanged.
+func (c *Config) Reload() (*Config, []Change, error) {
+	reloaded, err := c.reload()
+	if err != nil || reloaded == nil {
+		return nil, nil, err
+	}
+
+	previous, err := c.values()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, err := reloaded.values()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// options of the entries are already merged, so only their names are compared
+	previous["accounts"] = c.accountNames
+	current["accounts"] = reloaded.accountNames
+
+	var changes []Change
+
+	for _, option := range slices.Sorted(maps.Keys(mergeKeys(previous, current))) {
+		if !reflect.DeepEqual(previous[option], current[option]) {
+			changes = append(changes, Change{Option: option, Restart: slices.Contains(restartOptions, option)})
+		}
+	}
+
+	return reloaded, changes, nil
+}
+
+// WatchedFiles returns the configuration file and the files referred by it:
+// the secret files and the selectors file. Their changes reload the configuration.
+func (c *Config) WatchedFiles() []string {
+	files := append([]string{c.confFilePath}, c.secretPaths...)
+
+	if c.SelectorsFile != "" {
+		files = append(files, c.SelectorsFile)
+	}
+
+	for i, file := range files {
+		if absFile, err := filepath.Abs(file); err == nil {
+			files[i] = absFile
+		}
+	}
+
+	return files
+}
+
+// values returns the values of all options keyed by their paths, e.g. "budget_percent.fuel" or "schedules.depart".
+func (c *Config) values() (map[string]any, error) {
+	plain := *c
+	plain.Password = c.GetPassword()
+
+	out, err := yaml.Marshal(&plain)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make(map[string]any)
+
+	if err := yaml.Unmarshal(out, &options); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+	flattenValues(values, "", options)
+
+	return values, nil
+}
+
+// flattenValues adds the values of the nested mappings with the paths of their keys.
+func flattenValues(values map[string]any, prefix string, options map[string]any) {
+	for key, value := range options {
+		if nested, ok := value.(map[string]any); ok {
+			flattenValues(values, prefix+key+".", nested)
+
+			continue
+		}
+
+		values[prefix+key] = value
+	}
+}
+
+// mergeKeys returns the set of the keys of both maps.
+func mergeKeys(a, b map[string]any) map[string]struct{} {
+	keys := make(map[string]struct{}, len(a))
+
+	for key := range a {
+		keys[key] = struct{}{}
+	}
+
+	for key := range b {
+		keys[key] = struct{}{}
+	}
+
+	return keys
+}
+
+// String returns the option with the note if the restart is required.
+func (ch Change) String() string {
+	if ch.Restart {
+		return fmt.Sprintf("%s (restart required)", ch.Option)
+	}
+
+	return ch.Option
+}
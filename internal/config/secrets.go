@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envVarRegexp matches "${NAME}" and "${NAME:-default}" references to environment variables
+// and the "$${" escape of the literal "${".
+var envVarRegexp = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// secretFile binds the "*_file" option to the option whose value is read from the file.
+type secretFile struct {
+	option string
+	path   *string
+	value  *string
+}
+
+// secretFiles returns the options which values could be read from files, e.g. Docker secrets.
+func (c *Config) secretFiles() []secretFile {
+	return []secretFile{
+		{"username_file", &c.UserFile, &c.User},
+		{"password_file", &c.PasswordFile, &c.Password},
+		{"api_token_file", &c.ApiTokenFile, &c.ApiToken},
+	}
+}
+
+// secretValue holds the path and the value of the secret option loaded from the previous layer of the configuration.
+type secretValue struct {
+	path  string
+	value string
+}
+
+// secretValues returns the paths and the values of the secret options before the next layer
+// of the configuration is loaded, e.g. the account or the overrides.
+func (c *Config) secretValues() []secretValue {
+	var values []secretValue
+
+	for _, secret := range c.secretFiles() {
+		values = append(values, secretValue{*secret.path, *secret.value})
+	}
+
+	return values
+}
+
+// loadSecretFiles reads the values of the options from the "*_file" options, e.g. "password_file",
+// which are set by the last loaded layer of the configuration. The previous values are returned by secretValues.
+// The file has priority over the value in the same layer, the trailing line break is removed.
+// The value set by the layer over the inherited file replaces it, so the path of the file is cleared.
+// Paths of the read files are remembered, so their changes reload the configuration.
+func (c *Config) loadSecretFiles(previous []secretValue) error {
+	c.secretPaths = nil
+
+	for i, secret := range c.secretFiles() {
+		switch {
+		case *secret.path == "":
+			continue
+		case *secret.path != previous[i].path:
+			content, err := os.ReadFile(*secret.path)
+			if err != nil {
+				return fmt.Errorf("%s: %w", secret.option, err)
+			}
+
+			*secret.value = strings.TrimRight(string(content), "\r\n")
+		case *secret.value != previous[i].value:
+			// the account could override the value of the inherited file with its own one
+			*secret.path = ""
+
+			continue
+		}
+
+		c.secretPaths = append(c.secretPaths, *secret.path)
+	}
+
+	return nil
+}
+
+// expandEnv replaces references to environment variables in the scalar values of the YAML node.
+// Keys of mappings are not changed. The reference without the default value to the unset variable is the error.
+func expandEnv(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		// only values are expanded
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := expandEnv(node.Content[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, child := range node.Content {
+		if err := expandEnv(child); err != nil {
+			return err
+		}
+	}
+
+	if node.Kind != yaml.ScalarNode || !strings.Contains(node.Value, "$") {
+		return nil
+	}
+
+	var err error
+
+	value := envVarRegexp.ReplaceAllStringFunc(node.Value, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+
+		match := envVarRegexp.FindStringSubmatch(ref)
+
+		if envValue, ok := os.LookupEnv(match[1]); ok {
+			return envValue
+		}
+
+		if match[2] == "" && err == nil {
+			err = fmt.Errorf("line %d: environment variable %s is not set", node.Line, match[1])
+		}
+
+		return match[3]
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if value != node.Value {
+		node.Value = value
+
+		// the type of the plain value is resolved again, e.g. "${TIMEOUT}" could be the number
+		if node.Style&(yaml.TaggedStyle|yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			node.Tag = ""
+		}
+	}
+
+	return nil
+}
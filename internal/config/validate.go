@@ -0,0 +1,309 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// values of the options are the options of the select elements in the game UI
+	cateringDurationHours = []string{"6", "12", "18", "24", "48", "72", "96", "120", "144", "168"}
+	cateringAmountOptions = []string{"200", "500", "1000", "2000", "3000", "4000", "5000", "10000", "15000", "20000", "50000", "100000", "200000"}
+	aircraftWearPercents  = []string{"10", "20", "30", "40", "50", "60", "70", "80", "90"}
+	logLevels             = []string{"debug", "info", "warn", "error"}
+	scanTypes             = []string{"route_scanner", "airport_scanner"}
+	priceStrategies       = []string{PRICE_STRATEGY_FIXED, PRICE_STRATEGY_PERCENTILE}
+	// options which can't be set in the "accounts" list, e.g. the CSS selectors are shared by all accounts
+	topLevelOptions = []string{"selectors_file"}
+)
+
+// validators holds the checks of the configuration registered by other packages.
+var validators []func(c *Config) []FieldError
+
+// RegisterValidator adds the check of the configuration which is executed on every load,
+// e.g. the check of the service names registered by the bot.
+func RegisterValidator(validator func(c *Config) []FieldError) {
+	validators = append(validators, validator)
+}
+
+// FieldError describes the invalid option of the configuration.
+type FieldError struct {
+	// Field is the path of the option, e.g. "budget_percent.fuel" or "accounts[1].services[0]".
+	Field   string
+	Message string
+}
+
+// Error returns the path of the option with the description of the error.
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError holds all errors of the configuration of the account.
+type ValidationError struct {
+	Account string
+	Errors  []FieldError
+}
+
+// Error returns all errors of the configuration.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Error())
+	}
+
+	prefix := "invalid configuration"
+	if e.Account != "" {
+		prefix = fmt.Sprintf("invalid configuration of account %q", e.Account)
+	}
+
+	return prefix + ": " + strings.Join(messages, "; ")
+}
+
+// validate checks the loaded configuration and returns all found errors
+// with the errors of the overrides as the ValidationError.
+func (c *Config) validate(overrideErrs []FieldError) error {
+	errs := c.unknownKeys()
+	errs = append(errs, overrideErrs...)
+	errs = append(errs, c.invalidValues()...)
+
+	for _, validator := range validators {
+		errs = append(errs, validator(c)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Account: c.account, Errors: errs}
+}
+
+// invalidValues checks the values of the options.
+func (c *Config) invalidValues() []FieldError {
+	var errs []FieldError
+
+	check := func(valid bool, field string, format string, args ...any) {
+		if !valid {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	if err := validateAccountName(c.Name); err != nil {
+		errs = append(errs, FieldError{Field: "name", Message: err.Error()})
+	}
+
+	u, err := url.Parse(c.Url)
+	check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "url", "invalid URL %q", c.Url)
+	check(slices.Contains(logLevels, c.LogLevel), "log_level", "invalid level %q, possible values: %s", c.LogLevel, strings.Join(logLevels, ", "))
+
+	for field, percent := range map[string]float64{
+		"budget_percent.maintenance": c.BudgetPercent.Maintenance,
+		"budget_percent.marketing":   c.BudgetPercent.Marketing,
+		"budget_percent.fuel":        c.BudgetPercent.Fuel,
+		"fuel_critical_percent":      c.FuelCriticalPercent,
+		"price_history.percentile":   c.PriceHistory.Percentile,
+	} {
+		check(percent >= 0 && percent <= 100, field, "percentage %v is out of range 0..100", percent)
+	}
+
+	check(c.FuelPrice.Fuel >= 0, "good_price.fuel", "price %v is negative", c.FuelPrice.Fuel)
+	check(c.FuelPrice.Co2 >= 0, "good_price.co2", "price %v is negative", c.FuelPrice.Co2)
+	check(slices.Contains(priceStrategies, c.PriceStrategy), "price_strategy",
+		"invalid strategy %q, possible values: %s", c.PriceStrategy, strings.Join(priceStrategies, ", "))
+	for _, fuelType := range []string{"fuel", "co2"} {
+		settings := c.FuelTypes.ForType(fuelType)
+		field := "fuel_types." + fuelType
+
+		for option, percent := range map[string]*float64{
+			".critical_percent": settings.CriticalPercent,
+			".budget_percent":   settings.BudgetPercent,
+		} {
+			if percent != nil {
+				check(*percent >= 0 && *percent <= 100, field+option, "percentage %v is out of range 0..100", *percent)
+			}
+		}
+
+		check(settings.MaxPrice >= 0, field+".max_price", "price %v is negative", settings.MaxPrice)
+		check(settings.MinAmount >= 0, field+".min_amount", "amount %v is negative", settings.MinAmount)
+
+		for i, tier := range c.FuelTiers.ForType(fuelType) {
+			field := fmt.Sprintf("fuel_tiers.%s[%d]", fuelType, i)
+
+			check(tier.MaxPrice >= 0, field+".max_price", "price %v is negative", tier.MaxPrice)
+			check(tier.FillPercent > 0 && tier.FillPercent <= 100, field+".fill_percent", "percentage %v is out of range 1..100", tier.FillPercent)
+		}
+	}
+
+	check(c.FuelReserveHours >= 0, "fuel_reserve_hours", "value %v is negative", c.FuelReserveHours)
+	check(c.PriceHistory.Days > 0, "price_history.days", "period %d must be positive", c.PriceHistory.Days)
+	check(c.PriceStrategy != PRICE_STRATEGY_PERCENTILE || c.PriceHistory.File != "", "price_history.file",
+		"history file is required by the %q price strategy", PRICE_STRATEGY_PERCENTILE)
+
+	check(slices.Contains(cateringDurationHours, c.CateringDurationHours), "catering_duration_hours",
+		"invalid value %q, possible values: %s", c.CateringDurationHours, strings.Join(cateringDurationHours, ", "))
+	check(slices.Contains(cateringAmountOptions, c.CateringAmountOption), "catering_amount_option",
+		"invalid value %q, possible values: %s", c.CateringAmountOption, strings.Join(cateringAmountOptions, ", "))
+	check(slices.Contains(aircraftWearPercents, c.AircraftWearPercent), "aircraft_wear_percent",
+		"invalid value %q, possible values: %s", c.AircraftWearPercent, strings.Join(aircraftWearPercents, ", "))
+
+	for field, value := range map[string]int{
+		"hubs_maintenance_limit":             c.HubsMaintenanceLimit,
+		"aircraft_max_hours_to_check":        c.AircraftMaxHoursToCheck,
+		"aircraft_modify_limit":              c.AircraftModifyLimit,
+		"shutdown_grace_seconds":             c.ShutdownGraceSeconds,
+		"artifacts_keep":                     c.ArtifactsKeep,
+		"resilience.backoff_initial_seconds": c.Resilience.BackoffInitialSeconds,
+		"resilience.failure_threshold":       c.Resilience.FailureThreshold,
+		"min_runway_length":                  c.MinRunwayLength,
+		"price_history.min_samples":          c.PriceHistory.MinSamples,
+	} {
+		check(value >= 0, field, "value %d is negative", value)
+	}
+
+	check(c.TimeoutSeconds > 0, "timeout_seconds", "timeout %d must be positive", c.TimeoutSeconds)
+	check(c.Resilience.BackoffMaxSeconds >= c.Resilience.BackoffInitialSeconds, "resilience.backoff_max_seconds",
+		"maximal delay %d is less than backoff_initial_seconds %d", c.Resilience.BackoffMaxSeconds, c.Resilience.BackoffInitialSeconds)
+	check(c.Resilience.ProbeIntervalSeconds > 0, "resilience.probe_interval_seconds",
+		"interval %d must be positive", c.Resilience.ProbeIntervalSeconds)
+
+	if _, err := cron.ParseStandard(c.CronSchedule); err != nil {
+		errs = append(errs, FieldError{Field: "cron_schedule", Message: err.Error()})
+	}
+
+	for serviceName, spec := range c.Schedules {
+		if spec == "" {
+			continue
+		}
+
+		if _, err := cron.ParseStandard(spec); err != nil {
+			errs = append(errs, FieldError{Field: "schedules." + serviceName, Message: err.Error()})
+		}
+	}
+
+	if c.PrometheusAddress != "" {
+		if _, _, err := net.SplitHostPort(c.PrometheusAddress); err != nil {
+			errs = append(errs, FieldError{Field: "prometheus_address", Message: err.Error()})
+		}
+	}
+
+	check(slices.Contains(scanTypes, c.ScanType), "scan_type", "invalid scan type %q, possible values: %s", c.ScanType, strings.Join(scanTypes, ", "))
+	check(c.MinRouteDistanceKm >= 0, "min_route_range_km", "distance %d is negative", c.MinRouteDistanceKm)
+	check(c.MinRouteDistanceKm <= c.MaxRouteDistanceKm, "min_route_range_km",
+		"minimal distance %d is greater than max_route_range_km %d", c.MinRouteDistanceKm, c.MaxRouteDistanceKm)
+	check(c.ScanStepKm > 0, "scan_step_km", "step %d must be positive", c.ScanStepKm)
+
+	// errors of maps are sorted for the stable output
+	slices.SortStableFunc(errs, func(a, b FieldError) int { return strings.Compare(a.Field, b.Field) })
+
+	return errs
+}
+
+// unknownKeys returns the keys of the configuration file which are not the options, e.g. misspelled ones.
+func (c *Config) unknownKeys() []FieldError {
+	node, err := readYaml(c.confFilePath)
+	if err != nil || node.Kind == 0 {
+		// the file is already loaded, so it could only be changed in the meantime
+		return nil
+	}
+
+	root := node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	return unknownNodeKeys(root, reflect.TypeFor[Config](), "", true)
+}
+
+// topLevelNodeKeys returns the keys of the account mapping which are allowed only on the top level.
+func topLevelNodeKeys(node *yaml.Node, path string) []FieldError {
+	var errs []FieldError
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i].Value; slices.Contains(topLevelOptions, key) {
+			errs = append(errs, FieldError{
+				Field:   path + key,
+				Message: fmt.Sprintf("option is shared by all accounts, set it on the top level (line %d)", node.Content[i].Line),
+			})
+		}
+	}
+
+	return errs
+}
+
+// unknownNodeKeys returns the keys of the YAML mapping which are not the fields of the struct type.
+// The top-level mapping also contains the "accounts" list with the mappings of the same type.
+func unknownNodeKeys(node *yaml.Node, t reflect.Type, path string, topLevel bool) []FieldError {
+	var errs []FieldError
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		field := path + key
+
+		if topLevel && key == "accounts" {
+			for j, entry := range value.Content {
+				errs = append(errs, unknownNodeKeys(entry, t, fmt.Sprintf("accounts[%d].", j), false)...)
+				errs = append(errs, topLevelNodeKeys(entry, fmt.Sprintf("accounts[%d].", j))...)
+			}
+
+			continue
+		}
+
+		fieldType, ok := yamlField(t, key)
+		if !ok {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("unknown option (line %d)", node.Content[i].Line)})
+
+			continue
+		}
+
+		switch {
+		case fieldType.Kind() == reflect.Struct:
+			errs = append(errs, unknownNodeKeys(value, fieldType, field+".", false)...)
+		case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Struct && value.Kind == yaml.SequenceNode:
+			for j, entry := range value.Content {
+				errs = append(errs, unknownNodeKeys(entry, fieldType.Elem(), fmt.Sprintf("%s[%d].", field, j), false)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// yamlField returns the type of the struct field with the YAML key.
+func yamlField(t reflect.Type, key string) (reflect.Type, bool) {
+	index := yamlFieldIndex(t, key)
+	if index < 0 {
+		return nil, false
+	}
+
+	return t.Field(index).Type, true
+}
+
+// yamlFieldIndex returns the index of the struct field with the YAML key or -1 if there is no such field.
+// Fields without the "yaml" tag are not the options.
+func yamlFieldIndex(t reflect.Type, key string) int {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+
+		if field.IsExported() && name != "" && name != "-" && name == key {
+			return i
+		}
+	}
+
+	return -1
+}
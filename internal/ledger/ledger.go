@@ -0,0 +1,169 @@
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DAY_LAYOUT defines the date format used for grouping ledger entries by day.
+const DAY_LAYOUT string = "2006-01-02"
+
+// Entry represents a single purchase record in the ledger.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Account   string    `json:"account,omitempty"`
+	Service   string    `json:"service"`
+	Item      string    `json:"item"`
+	Details   string    `json:"details,omitempty"`
+	Quantity  float64   `json:"quantity"`
+	UnitPrice float64   `json:"unit_price"`
+	Total     float64   `json:"total"`
+}
+
+// Total holds the summary of purchases for a single day and item.
+type Total struct {
+	Day   string
+	Item  string
+	Count int
+	Total float64
+}
+
+// Ledger is an append-only JSON Lines file with all purchases made by the bot.
+type Ledger struct {
+	filePath string
+	mu       sync.Mutex
+}
+
+// New creates a new Ledger instance for the specified file path.
+func New(filePath string) *Ledger {
+	return &Ledger{
+		filePath: filePath,
+	}
+}
+
+// FilePath returns the path of the ledger file.
+func (l *Ledger) FilePath() string {
+	return l.filePath
+}
+
+// Append writes the entry to the end of the ledger file.
+func (l *Ledger) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	slog.Debug("ledger entry added", "file", l.filePath, "entry", e)
+
+	return nil
+}
+
+// Read returns all entries which have been recorded since the specified time.
+// Missing ledger file is treated as an empty ledger.
+func (l *Ledger) Read(since time.Time) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		var e Entry
+
+		lineNumber++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			slog.Warn("skip malformed ledger entry", "file", l.filePath, "line", lineNumber, "error", err)
+
+			continue
+		}
+
+		if e.Timestamp.Before(since) {
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Summarize returns totals of entries grouped by day (in the specified location) and item,
+// sorted by day and item.
+func Summarize(entries []Entry, loc *time.Location) []Total {
+	type totalKey struct {
+		day  string
+		item string
+	}
+
+	totalsMap := make(map[totalKey]*Total)
+
+	for _, e := range entries {
+		key := totalKey{
+			day:  e.Timestamp.In(loc).Format(DAY_LAYOUT),
+			item: e.Item,
+		}
+
+		t, ok := totalsMap[key]
+		if !ok {
+			t = &Total{Day: key.day, Item: key.item}
+			totalsMap[key] = t
+		}
+
+		t.Count++
+		t.Total += e.Total
+	}
+
+	totals := make([]Total, 0, len(totalsMap))
+
+	for _, t := range totalsMap {
+		totals = append(totals, *t)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Day != totals[j].Day {
+			return totals[i].Day < totals[j].Day
+		}
+
+		return totals[i].Item < totals[j].Item
+	})
+
+	return totals
+}
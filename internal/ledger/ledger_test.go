@@ -0,0 +1,64 @@
+package ledger
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	l := New(filepath.Join(t.TempDir(), "ledger.jsonl"))
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entries := []Entry{
+		{Timestamp: now.Add(-48 * time.Hour), Service: "buy_fuel", Item: "fuel", Quantity: 1000, UnitPrice: 500, Total: 500},
+		{Timestamp: now, Service: "marketing", Item: "campaign", Details: "Eco friendly", Quantity: 1, UnitPrice: 1500, Total: 1500},
+	}
+
+	for _, e := range entries {
+		if err := l.Append(e); err != nil {
+			t.Fatalf(`Append(%+v) returned unexpected error: %+v`, e, err)
+		}
+	}
+
+	result, err := l.Read(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf(`Read() returned unexpected error: %+v`, err)
+	}
+
+	if !slices.Equal(result, entries[1:]) {
+		t.Errorf(`Read() returned '%+v', expected '%+v'`, result, entries[1:])
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	result, err := New(filepath.Join(t.TempDir(), "missing.jsonl")).Read(time.Time{})
+	if err != nil || len(result) != 0 {
+		t.Errorf(`Read() returned '%+v', '%+v', expected empty result without error`, result, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	day1 := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{Timestamp: day2, Item: "fuel", Total: 100},
+		{Timestamp: day1, Item: "fuel", Total: 200},
+		{Timestamp: day1, Item: "co2", Total: 50},
+		{Timestamp: day1.Add(time.Hour), Item: "fuel", Total: 300},
+	}
+
+	expected := []Total{
+		{Day: "2026-10-16", Item: "co2", Count: 1, Total: 50},
+		{Day: "2026-10-16", Item: "fuel", Count: 2, Total: 500},
+		{Day: "2026-10-17", Item: "fuel", Count: 1, Total: 100},
+	}
+
+	result := Summarize(entries, time.UTC)
+
+	if !slices.Equal(result, expected) {
+		t.Errorf(`Summarize() returned '%+v', expected '%+v'`, result, expected)
+	}
+}
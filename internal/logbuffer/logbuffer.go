@@ -0,0 +1,101 @@
+package logbuffer
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Buffer keeps the last log lines in memory, so they can be shown on the dashboard.
+type Buffer struct {
+	mu    sync.Mutex
+	lines []string
+	size  int
+	next  int
+	full  bool
+}
+
+// New creates a new Buffer which keeps the last size log lines.
+func New(size int) *Buffer {
+	return &Buffer{
+		lines: make([]string, size),
+		size:  size,
+	}
+}
+
+// Write adds the log line to the buffer, overwriting the oldest line if the buffer is full.
+// The slog.TextHandler writes every record with a single Write call.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.size == 0 {
+		return len(p), nil
+	}
+
+	b.lines[b.next] = strings.TrimRight(string(p), "\n")
+	b.next = (b.next + 1) % b.size
+
+	if b.next == 0 {
+		b.full = true
+	}
+
+	return len(p), nil
+}
+
+// Lines returns the kept log lines from the oldest to the newest.
+func (b *Buffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]string{}, b.lines[:b.next]...)
+	}
+
+	return append(append([]string{}, b.lines[b.next:]...), b.lines[:b.next]...)
+}
+
+// Handler returns the slog.Handler which passes records to the next handler
+// and keeps the ones enabled by it in the buffer.
+func (b *Buffer) Handler(next slog.Handler) slog.Handler {
+	return &handler{
+		next: next,
+		text: slog.NewTextHandler(b, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	}
+}
+
+// handler writes records to both the next handler and the buffer.
+type handler struct {
+	next slog.Handler
+	text slog.Handler
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle writes the record to the buffer and passes it to the next handler.
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	// the buffer never fails, so its error is ignored
+	_ = h.text.Handle(ctx, r.Clone())
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a new handler with the attributes added to both handlers.
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{
+		next: h.next.WithAttrs(attrs),
+		text: h.text.WithAttrs(attrs),
+	}
+}
+
+// WithGroup returns a new handler with the group added to both handlers.
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{
+		next: h.next.WithGroup(name),
+		text: h.text.WithGroup(name),
+	}
+}
@@ -0,0 +1,58 @@
+package logbuffer
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	testCases := map[string]struct {
+		size     int
+		writes   int
+		expected []string
+	}{
+		"test01": {3, 0, []string{}},
+		"test02": {3, 2, []string{"line 0", "line 1"}},
+		"test03": {3, 3, []string{"line 0", "line 1", "line 2"}},
+		"test04": {3, 5, []string{"line 2", "line 3", "line 4"}},
+		"test05": {0, 2, []string{}},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			b := New(testData.size)
+
+			for i := range testData.writes {
+				fmt.Fprintf(b, "line %d\n", i)
+			}
+
+			if lines := b.Lines(); !slices.Equal(lines, testData.expected) {
+				t.Errorf(`Lines() returned '%q', expected '%q'`, lines, testData.expected)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	b := New(10)
+	next := slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(b.Handler(next)).With("service", "buy_fuel")
+
+	logger.Debug("hidden debug message")
+	logger.Info("fuel bought", "amount", 1000)
+
+	lines := b.Lines()
+	if len(lines) != 1 {
+		t.Fatalf(`Lines() returned '%q', expected one line`, lines)
+	}
+
+	for _, expected := range []string{"level=INFO", `msg="fuel bought"`, "service=buy_fuel", "amount=1000"} {
+		if !strings.Contains(lines[0], expected) {
+			t.Errorf(`log line '%s' doesn't contain '%s'`, lines[0], expected)
+		}
+	}
+}
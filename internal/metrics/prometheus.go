@@ -36,11 +36,23 @@ type Metrics struct {
 	FuelHolding                     *prometheus.GaugeVec
 	FuelLimit                       *prometheus.GaugeVec
 	FuelPrice                       *prometheus.GaugeVec
+	FuelPriceThreshold              *prometheus.GaugeVec
+	FuelBurnRate                    *prometheus.GaugeVec
+	FuelHoursUntilEmpty             *prometheus.GaugeVec
+	FuelDecision                    *prometheus.GaugeVec
 	AllianceMemberSharePrice        *prometheus.GaugeVec
 	AllianceMemberContributedTotal  *prometheus.GaugeVec
 	AllianceMemberContributedPerDay *prometheus.GaugeVec
 	AllianceMemberContributedSeason *prometheus.GaugeVec
 	AllianceMemberFlightsTotal      *prometheus.GaugeVec
+	ServiceUp                       *prometheus.GaugeVec
+	ServiceErrorsTotal              *prometheus.CounterVec
+	ServiceLastSuccessTimestamp     *prometheus.GaugeVec
+	BreakerState                    prometheus.Gauge
+	ConsecutiveFailures             prometheus.Gauge
+	NextAttemptTimestamp            prometheus.Gauge
+	ConfigReloadFailuresTotal       prometheus.Counter
+	ConfigLastReloadSuccessful      prometheus.Gauge
 }
 
 // New initializes and returns a new Metrics instance with all Prometheus metrics defined.
@@ -240,6 +252,38 @@ func New() *Metrics {
 			},
 			[]string{"type"},
 		),
+		FuelPriceThreshold: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "market_fuel_price_threshold",
+				Help:      "Maximal price of buying fuel by fuel type: good_price or the percentile of the observed prices.",
+			},
+			[]string{"type"},
+		),
+		FuelBurnRate: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "fuel_burn_rate",
+				Help:      "Estimated fuel consumption per hour by fuel type.",
+			},
+			[]string{"type"},
+		),
+		FuelHoursUntilEmpty: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "fuel_hours_until_empty",
+				Help:      "Estimated hours until the fuel holding is empty by fuel type.",
+			},
+			[]string{"type"},
+		),
+		FuelDecision: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "fuel_decision",
+				Help:      "Reason of the last purchase decision by fuel type, the value is always 1.",
+			},
+			[]string{"type", "reason"},
+		),
 		AllianceMemberSharePrice: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
@@ -280,11 +324,70 @@ func New() *Metrics {
 			},
 			[]string{"uid", "name", "alliance_id", "alliance_name"},
 		),
+		ServiceUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "service_up",
+				Help:      "Was the last execution of the service successful.",
+			},
+			[]string{"service"},
+		),
+		ServiceErrorsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "service_errors_total",
+				Help:      "Total number of failed service executions.",
+			},
+			[]string{"service"},
+		),
+		ServiceLastSuccessTimestamp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "service_last_success_timestamp_seconds",
+				Help:      "Time of the last successful service execution since unix epoch in seconds.",
+			},
+			[]string{"service"},
+		),
+		BreakerState: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "circuit_breaker_state",
+				Help:      "State of the circuit breaker: 0 - closed, 1 - half-open, 2 - open.",
+			},
+		),
+		ConsecutiveFailures: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "run_consecutive_failures",
+				Help:      "Number of consecutive failed runs.",
+			},
+		),
+		NextAttemptTimestamp: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "run_next_attempt_timestamp_seconds",
+				Help:      "Time before which runs are skipped after failures since unix epoch in seconds. Zero if runs aren't delayed.",
+			},
+		),
+		ConfigReloadFailuresTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "config_reload_failures_total",
+				Help:      "Total number of rejected reloads of the changed configuration.",
+			},
+		),
+		ConfigLastReloadSuccessful: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "config_last_reload_successful",
+				Help:      "Whether the last reload of the configuration was successful: 1 - the current configuration is applied, 0 - the previous one is kept.",
+			},
+		),
 	}
 }
 
 // RegisterMetrics registers all Prometheus metrics with the provided registry.
-func (m *Metrics) RegisterMetrics(registry *prometheus.Registry) {
+func (m *Metrics) RegisterMetrics(registry prometheus.Registerer) {
 	registry.MustRegister(
 		m.Up,
 		m.StartTimeSeconds,
@@ -312,10 +415,22 @@ func (m *Metrics) RegisterMetrics(registry *prometheus.Registry) {
 		m.FuelHolding,
 		m.FuelLimit,
 		m.FuelPrice,
+		m.FuelPriceThreshold,
+		m.FuelBurnRate,
+		m.FuelHoursUntilEmpty,
+		m.FuelDecision,
 		m.AllianceMemberSharePrice,
 		m.AllianceMemberContributedTotal,
 		m.AllianceMemberContributedPerDay,
 		m.AllianceMemberContributedSeason,
 		m.AllianceMemberFlightsTotal,
+		m.ServiceUp,
+		m.ServiceErrorsTotal,
+		m.ServiceLastSuccessTimestamp,
+		m.BreakerState,
+		m.ConsecutiveFailures,
+		m.NextAttemptTimestamp,
+		m.ConfigReloadFailuresTotal,
+		m.ConfigLastReloadSuccessful,
 	)
 }
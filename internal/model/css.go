@@ -1,6 +1,8 @@
 package model
 
-const (
+// CSS selectors of the game UI. They are variables, so they can be overridden
+// by the selector overrides file without rebuilding, see ApplySelectorOverrides.
+var (
 	// Login screen
 
 	BUTTON_PLAY_NOW     string = "button.play-now"                  // "Play free now" button
@@ -35,6 +37,7 @@ const (
 	BUTTON_COMMON_TAB2        string = "#popBtn2"                                                                                // switch to tab 2
 	BUTTON_COMMON_TAB3        string = "#popBtn3"                                                                                // switch to tab 3
 	BUTTON_COMMON_CLOSE_POPUP string = `span[onclick="closePop();document.getElementById('rewardPopup').style.display='none';"]` // close pop-up window
+	ELEMENT_COMMON_POPUP      string = "div#popup"                                                                               // pop-up window, captured on failures
 
 	// "Flight info" elements (left side of main screen)
 
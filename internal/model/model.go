@@ -2,8 +2,6 @@ package model
 
 import (
 	"fmt"
-
-	"github.com/chromedp/cdproto/cdp"
 )
 
 type MaintenanceType int
@@ -62,8 +60,26 @@ type Fuel struct {
 	Holding  float64
 	Capacity float64
 	IsFull   bool
+	// BurnRate is the estimated consumption per hour, zero if it's unknown yet
+	BurnRate float64
+	// Decision is the reason why the fuel has been bought or not, one of FUEL_DECISION_*
+	Decision string
 }
 
+// Reasons of the purchase decision of the fuel type.
+const (
+	FUEL_DECISION_FULL          string = "full"
+	FUEL_DECISION_CRITICAL      string = "critical"
+	FUEL_DECISION_GOOD_PRICE    string = "good_price"
+	FUEL_DECISION_PRICE_TIER    string = "price_tier"
+	FUEL_DECISION_FORECAST      string = "forecast"
+	FUEL_DECISION_TOO_EXPENSIVE string = "too_expensive"
+	FUEL_DECISION_MAX_PRICE     string = "above_max_price"
+	FUEL_DECISION_ABOVE_LEVEL   string = "above_level"
+	FUEL_DECISION_MIN_AMOUNT    string = "below_min_amount"
+	FUEL_DECISION_NO_BUDGET     string = "no_budget"
+)
+
 // Aircraft represents an aircraft in the fleet.
 type Aircraft struct {
 	RegNumber string
@@ -83,19 +99,18 @@ type MarketingCompany struct {
 
 // Hub represents an airport hub with various statistics.
 type Hub struct {
-	Departures    float64
-	Arrivals      float64
-	PaxDeparted   float64
-	PaxArrived    float64
-	HasCatering   bool
-	NeedsRepair   bool
-	HubCdpNode    *cdp.Node
-	LoungeCdpNode *cdp.Node
+	Name        string
+	Departures  float64
+	Arrivals    float64
+	PaxDeparted float64
+	PaxArrived  float64
+	HasCatering bool
+	NeedsRepair bool
 }
 
 // String returns a string representation of the Hub struct.
 func (h Hub) String() string {
-	return fmt.Sprint("{Departures:", h.Departures, ", Arrivals:", h.Arrivals,
+	return fmt.Sprint("{Name:", h.Name, ", Departures:", h.Departures, ", Arrivals:", h.Arrivals,
 		", PaxDeparted:", h.PaxDeparted, ", PaxArrived:", h.PaxArrived,
 		", HasCatering:", h.HasCatering, ", NeedsRepair:", h.NeedsRepair, "}")
 }
@@ -123,3 +138,12 @@ type Route struct {
 	DemandLarge int
 	DemandHeavy int
 }
+
+// Purchase represents a single spending decision made by the bot.
+type Purchase struct {
+	Item      string
+	Details   string
+	Quantity  float64
+	UnitPrice float64
+	Total     float64
+}
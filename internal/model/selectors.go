@@ -0,0 +1,283 @@
+package model
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// selectors maps the names of the CSS selectors to their variables, so the selectors can be overridden by name.
+var selectors = map[string]*string{
+	"BUTTON_PLAY_NOW":                                    &BUTTON_PLAY_NOW,
+	"BUTTON_LOGIN":                                       &BUTTON_LOGIN,
+	"TEXT_FIELD_LOGIN":                                   &TEXT_FIELD_LOGIN,
+	"TEXT_FIELD_PASSWORD":                                &TEXT_FIELD_PASSWORD,
+	"CHECKBOX_REMEMBER":                                  &CHECKBOX_REMEMBER,
+	"BUTTON_AUTH":                                        &BUTTON_AUTH,
+	"OVERLAY_LOADING":                                    &OVERLAY_LOADING,
+	"BUTTON_MAIN_HUBS":                                   &BUTTON_MAIN_HUBS,
+	"BUTTON_MAIN_ACCOUNT":                                &BUTTON_MAIN_ACCOUNT,
+	"BUTTON_MAIN_COMPANY":                                &BUTTON_MAIN_COMPANY,
+	"BUTTON_MAIN_FLEET":                                  &BUTTON_MAIN_FLEET,
+	"BUTTON_MAIN_FUEL":                                   &BUTTON_MAIN_FUEL,
+	"BUTTON_MAIN_MAINTENANCE":                            &BUTTON_MAIN_MAINTENANCE,
+	"BUTTON_MAIN_FINANCE":                                &BUTTON_MAIN_FINANCE,
+	"BUTTON_MAIN_BONUS":                                  &BUTTON_MAIN_BONUS,
+	"ICON_FREE_REWARDS":                                  &ICON_FREE_REWARDS,
+	"LIST_ACCOUNT_ACCOUNTS":                              &LIST_ACCOUNT_ACCOUNTS,
+	"TEXT_ACCOUNT_ACCOUNT_NAME":                          &TEXT_ACCOUNT_ACCOUNT_NAME,
+	"TEXT_ACCOUNT_ACCOUNT_BALANCE":                       &TEXT_ACCOUNT_ACCOUNT_BALANCE,
+	"BUTTON_COMMON_TAB1":                                 &BUTTON_COMMON_TAB1,
+	"BUTTON_COMMON_TAB2":                                 &BUTTON_COMMON_TAB2,
+	"BUTTON_COMMON_TAB3":                                 &BUTTON_COMMON_TAB3,
+	"BUTTON_COMMON_CLOSE_POPUP":                          &BUTTON_COMMON_CLOSE_POPUP,
+	"ELEMENT_COMMON_POPUP":                               &ELEMENT_COMMON_POPUP,
+	"ICON_FI_LOUNGE_ALERT":                               &ICON_FI_LOUNGE_ALERT,
+	"BUTTON_ALLIANCE_INFO":                               &BUTTON_ALLIANCE_INFO,
+	"BUTTON_FI_OVERVIEW":                                 &BUTTON_FI_OVERVIEW,
+	"BUTTON_FI_DEPART_ALL":                               &BUTTON_FI_DEPART_ALL,
+	"TEXT_FI_DEPART_AMOUNT":                              &TEXT_FI_DEPART_AMOUNT,
+	"TEXT_OVERVIEW_AIRLINE_REPUTATION":                   &TEXT_OVERVIEW_AIRLINE_REPUTATION,
+	"TEXT_OVERVIEW_CARGO_REPUTATION":                     &TEXT_OVERVIEW_CARGO_REPUTATION,
+	"TEXT_OVERVIEW_FLEET_SIZE":                           &TEXT_OVERVIEW_FLEET_SIZE,
+	"TEXT_OVERVIEW_AC_PENDING_DELIVERY":                  &TEXT_OVERVIEW_AC_PENDING_DELIVERY,
+	"TEXT_OVERVIEW_ROUTES":                               &TEXT_OVERVIEW_ROUTES,
+	"TEXT_OVERVIEW_HUBS":                                 &TEXT_OVERVIEW_HUBS,
+	"TEXT_OVERVIEW_AC_PENDING_MAINTENANCE":               &TEXT_OVERVIEW_AC_PENDING_MAINTENANCE,
+	"TEXT_OVERVIEW_HANGAR_CAPACITY":                      &TEXT_OVERVIEW_HANGAR_CAPACITY,
+	"TEXT_OVERVIEW_AC_INFLIGHT":                          &TEXT_OVERVIEW_AC_INFLIGHT,
+	"TEXT_OVERVIEW_SHARE_PRICE":                          &TEXT_OVERVIEW_SHARE_PRICE,
+	"TEXT_OVERVIEW_FLIGHTS_OPERATED":                     &TEXT_OVERVIEW_FLIGHTS_OPERATED,
+	"TEXT_OVERVIEW_PASSENGERS_ECONOMY_TRANSPORTED":       &TEXT_OVERVIEW_PASSENGERS_ECONOMY_TRANSPORTED,
+	"TEXT_OVERVIEW_PASSENGERS_BUSINESS_TRANSPORTED":      &TEXT_OVERVIEW_PASSENGERS_BUSINESS_TRANSPORTED,
+	"TEXT_OVERVIEW_PASSENGERS_FIRST_TRANSPORTED":         &TEXT_OVERVIEW_PASSENGERS_FIRST_TRANSPORTED,
+	"TEXT_OVERVIEW_CARGO_TRANSPORTED_LARGE":              &TEXT_OVERVIEW_CARGO_TRANSPORTED_LARGE,
+	"TEXT_OVERVIEW_CARGO_TRANSPORTED_HEAVY":              &TEXT_OVERVIEW_CARGO_TRANSPORTED_HEAVY,
+	"LIST_ALLIANCE_MEMBERS":                              &LIST_ALLIANCE_MEMBERS,
+	"TEXT_ALLIANCE_MEMBER_ID":                            &TEXT_ALLIANCE_MEMBER_ID,
+	"TEXT_ALLIANCE_MEMBER_NAME":                          &TEXT_ALLIANCE_MEMBER_NAME,
+	"TEXT_ALLIANCE_MEMBER_SHARE_PRICE":                   &TEXT_ALLIANCE_MEMBER_SHARE_PRICE,
+	"TEXT_ALLIANCE_MEMBER_CONTRIBUTED_TOTAL":             &TEXT_ALLIANCE_MEMBER_CONTRIBUTED_TOTAL,
+	"TEXT_ALLIANCE_MEMBER_CONTRIBUTED_PER_DAY":           &TEXT_ALLIANCE_MEMBER_CONTRIBUTED_PER_DAY,
+	"TEXT_ALLIANCE_MEMBER_FLIGHTS":                       &TEXT_ALLIANCE_MEMBER_FLIGHTS,
+	"TEXT_ALLIANCE_MEMBER_SEASON_MONEY":                  &TEXT_ALLIANCE_MEMBER_SEASON_MONEY,
+	"TEXT_ALLIANCE_CONTRIBUTED_TOTAL":                    &TEXT_ALLIANCE_CONTRIBUTED_TOTAL,
+	"TEXT_ALLIANCE_CONTRIBUTED_PER_DAY":                  &TEXT_ALLIANCE_CONTRIBUTED_PER_DAY,
+	"TEXT_ALLIANCE_FLIGHTS":                              &TEXT_ALLIANCE_FLIGHTS,
+	"TEXT_ALLIANCE_SEASON_MONEY":                         &TEXT_ALLIANCE_SEASON_MONEY,
+	"TEXT_ALLIANCE_PG_DETAIL_NAME":                       &TEXT_ALLIANCE_PG_DETAIL_NAME,
+	"BUTTON_HUBS_LOUNGES_MAINTENANCE":                    &BUTTON_HUBS_LOUNGES_MAINTENANCE,
+	"LIST_HUBS_LOUNGES":                                  &LIST_HUBS_LOUNGES,
+	"TEXT_HUBS_LOUNGES_LOUNGE_NAME":                      &TEXT_HUBS_LOUNGES_LOUNGE_NAME,
+	"TEXT_HUBS_LOUNGES_LOUNGE_WEAR_PERCENT":              &TEXT_HUBS_LOUNGES_LOUNGE_WEAR_PERCENT,
+	"TEXT_HUBS_LOUNGES_LOUNGE_REPAIR_COST":               &TEXT_HUBS_LOUNGES_LOUNGE_REPAIR_COST,
+	"BUTTON_HUBS_LOUNGES_LOUNGE_REPAIR":                  &BUTTON_HUBS_LOUNGES_LOUNGE_REPAIR,
+	"BUTTON_HUBS_LOUNGES_BACK_TO_HUBS":                   &BUTTON_HUBS_LOUNGES_BACK_TO_HUBS,
+	"LIST_HUBS_HUBS":                                     &LIST_HUBS_HUBS,
+	"ELEMENT_HUB":                                        &ELEMENT_HUB,
+	"TEXT_HUBS_HUB_NAME":                                 &TEXT_HUBS_HUB_NAME,
+	"TEXT_HUBS_HUB_DEPARTURES":                           &TEXT_HUBS_HUB_DEPARTURES,
+	"TEXT_HUBS_HUB_ARRIVALS":                             &TEXT_HUBS_HUB_ARRIVALS,
+	"TEXT_HUBS_HUB_PAX_DEPARTED":                         &TEXT_HUBS_HUB_PAX_DEPARTED,
+	"TEXT_HUBS_HUB_PAX_ARRIVED":                          &TEXT_HUBS_HUB_PAX_ARRIVED,
+	"BUTTON_HUBS_HUB_MANAGE":                             &BUTTON_HUBS_HUB_MANAGE,
+	"TEXT_HUBS_HUB_MANAGE_REPAIR_COST":                   &TEXT_HUBS_HUB_MANAGE_REPAIR_COST,
+	"BUTTON_HUBS_HUB_MANAGE_REPAIR":                      &BUTTON_HUBS_HUB_MANAGE_REPAIR,
+	"BUTTON_HUBS_HUB_MANAGE_BACK":                        &BUTTON_HUBS_HUB_MANAGE_BACK,
+	"ICON_HUBS_CATERING":                                 &ICON_HUBS_CATERING,
+	"BUTTON_HUBS_ADD_CATERING":                           &BUTTON_HUBS_ADD_CATERING,
+	"ELEM_HUBS_CATERING_OPTION_3":                        &ELEM_HUBS_CATERING_OPTION_3,
+	"SELECT_HUBS_CATERING_DURATION":                      &SELECT_HUBS_CATERING_DURATION,
+	"SELECT_HUBS_CATERING_AMOUNT":                        &SELECT_HUBS_CATERING_AMOUNT,
+	"TEXT_HUBS_CATERING_COST":                            &TEXT_HUBS_CATERING_COST,
+	"BUTTON_HUBS_CATERING_BUY":                           &BUTTON_HUBS_CATERING_BUY,
+	"TEXT_COMPANY_RANK":                                  &TEXT_COMPANY_RANK,
+	"TEXT_COMPANY_STAFF_TRAINING_POINTS":                 &TEXT_COMPANY_STAFF_TRAINING_POINTS,
+	"TEXT_COMPANY_STAFF_PILOT_SALARY":                    &TEXT_COMPANY_STAFF_PILOT_SALARY,
+	"TEXT_COMPANY_STAFF_PILOT_MORALE":                    &TEXT_COMPANY_STAFF_PILOT_MORALE,
+	"BUTTON_COMPANY_STAFF_PILOT_SALARY_UP":               &BUTTON_COMPANY_STAFF_PILOT_SALARY_UP,
+	"BUTTON_COMPANY_STAFF_PILOT_SALARY_DOWN":             &BUTTON_COMPANY_STAFF_PILOT_SALARY_DOWN,
+	"TEXT_COMPANY_STAFF_CREW_SALARY":                     &TEXT_COMPANY_STAFF_CREW_SALARY,
+	"TEXT_COMPANY_STAFF_CREW_MORALE":                     &TEXT_COMPANY_STAFF_CREW_MORALE,
+	"BUTTON_COMPANY_STAFF_CREW_SALARY_UP":                &BUTTON_COMPANY_STAFF_CREW_SALARY_UP,
+	"BUTTON_COMPANY_STAFF_CREW_SALARY_DOWN":              &BUTTON_COMPANY_STAFF_CREW_SALARY_DOWN,
+	"TEXT_COMPANY_STAFF_ENGINEER_SALARY":                 &TEXT_COMPANY_STAFF_ENGINEER_SALARY,
+	"TEXT_COMPANY_STAFF_ENGINEER_MORALE":                 &TEXT_COMPANY_STAFF_ENGINEER_MORALE,
+	"BUTTON_COMPANY_STAFF_ENGINEER_SALARY_UP":            &BUTTON_COMPANY_STAFF_ENGINEER_SALARY_UP,
+	"BUTTON_COMPANY_STAFF_ENGINEER_SALARY_DOWN":          &BUTTON_COMPANY_STAFF_ENGINEER_SALARY_DOWN,
+	"TEXT_COMPANY_STAFF_TECHNICIAN_SALARY":               &TEXT_COMPANY_STAFF_TECHNICIAN_SALARY,
+	"TEXT_COMPANY_STAFF_TECHNICIAN_MORALE":               &TEXT_COMPANY_STAFF_TECHNICIAN_MORALE,
+	"BUTTON_COMPANY_STAFF_TECHNICIAN_SALARY_UP":          &BUTTON_COMPANY_STAFF_TECHNICIAN_SALARY_UP,
+	"BUTTON_COMPANY_STAFF_TECHNICIAN_SALARY_DOWN":        &BUTTON_COMPANY_STAFF_TECHNICIAN_SALARY_DOWN,
+	"LINK_FLEET_RESEARCH_CUSTOM_DEPARTURE":               &LINK_FLEET_RESEARCH_CUSTOM_DEPARTURE,
+	"SELECT_FLEET_RESEARCH_COUNTRY_SELECTOR":             &SELECT_FLEET_RESEARCH_COUNTRY_SELECTOR,
+	"LIST_FLEET_RESEARCH_COUNTRY_OPTIONS":                &LIST_FLEET_RESEARCH_COUNTRY_OPTIONS,
+	"SELECT_FLEET_RESEARCH_DEPARTING_FROM":               &SELECT_FLEET_RESEARCH_DEPARTING_FROM,
+	"LIST_FLEET_RESEARCH_DEPARTING_FROM":                 &LIST_FLEET_RESEARCH_DEPARTING_FROM,
+	"TEXTFIELD_FLEET_RESEARCH_MAX_DISTANCE":              &TEXTFIELD_FLEET_RESEARCH_MAX_DISTANCE,
+	"TEXTFIELD_FLEET_RESEARCH_MIN_RUNWAY":                &TEXTFIELD_FLEET_RESEARCH_MIN_RUNWAY,
+	"BUTTON_FLEET_RESEARCH_SEARCH":                       &BUTTON_FLEET_RESEARCH_SEARCH,
+	"LIST_FLEET_RESEARCH_SEARCH_RESULTS":                 &LIST_FLEET_RESEARCH_SEARCH_RESULTS,
+	"TEXT_FLEET_RESEARCH_ROUTE_FROM":                     &TEXT_FLEET_RESEARCH_ROUTE_FROM,
+	"TEXT_FLEET_RESEARCH_ROUTE_TO":                       &TEXT_FLEET_RESEARCH_ROUTE_TO,
+	"TEXT_FUEL_FUEL_PRICE":                               &TEXT_FUEL_FUEL_PRICE,
+	"TEXT_FUEL_FUEL_HOLDING":                             &TEXT_FUEL_FUEL_HOLDING,
+	"TEXT_FUEL_FUEL_CAPACITY":                            &TEXT_FUEL_FUEL_CAPACITY,
+	"TEXT_FIELD_FUEL_AMOUNT":                             &TEXT_FIELD_FUEL_AMOUNT,
+	"BUTTON_FUEL_BUY":                                    &BUTTON_FUEL_BUY,
+	"BUTTON_MAINTENANCE_BULK_REPAIR":                     &BUTTON_MAINTENANCE_BULK_REPAIR,
+	"BUTTON_MAINTENANCE_BULK_ACHECK":                     &BUTTON_MAINTENANCE_BULK_ACHECK,
+	"BUTTON_MAINTENANCE_BASE_ONLY":                       &BUTTON_MAINTENANCE_BASE_ONLY,
+	"LIST_MAINTENANCE_AC_LIST":                           &LIST_MAINTENANCE_AC_LIST,
+	"TEXT_MAINTENANCE_AC_REG_NUMBER":                     &TEXT_MAINTENANCE_AC_REG_NUMBER,
+	"TEXT_MAINTENANCE_AC_TYPE":                           &TEXT_MAINTENANCE_AC_TYPE,
+	"BUTTON_MAINTENANCE_MODIFY":                          &BUTTON_MAINTENANCE_MODIFY,
+	"CHECKBOX_MAINTENANCE_MODIFY_MOD1":                   &CHECKBOX_MAINTENANCE_MODIFY_MOD1,
+	"CHECKBOX_MAINTENANCE_MODIFY_MOD2":                   &CHECKBOX_MAINTENANCE_MODIFY_MOD2,
+	"CHECKBOX_MAINTENANCE_MODIFY_MOD3":                   &CHECKBOX_MAINTENANCE_MODIFY_MOD3,
+	"TEXT_MAINTENANCE_MODIFY_TOTAL_COST":                 &TEXT_MAINTENANCE_MODIFY_TOTAL_COST,
+	"BUTTON_MAINTENANCE_PLAN_MODIFY":                     &BUTTON_MAINTENANCE_PLAN_MODIFY,
+	"SELECT_MAINTENANCE_BULK_REPAIR_PERCENT":             &SELECT_MAINTENANCE_BULK_REPAIR_PERCENT,
+	"TEXT_MAINTENANCE_BULK_REPAIR_COST":                  &TEXT_MAINTENANCE_BULK_REPAIR_COST,
+	"BUTTON_MAINTENANCE_BULK_REPAIR_PLAN":                &BUTTON_MAINTENANCE_BULK_REPAIR_PLAN,
+	"LIST_MAINTENANCE_BULK_ACHECK_AC_LIST":               &LIST_MAINTENANCE_BULK_ACHECK_AC_LIST,
+	"TEXT_MAINTENANCE_BULK_ACHECK_HOURS":                 &TEXT_MAINTENANCE_BULK_ACHECK_HOURS,
+	"TEXT_MAINTENANCE_BULK_ACHECK_COST":                  &TEXT_MAINTENANCE_BULK_ACHECK_COST,
+	"BUTTON_MAINTENANCE_BULK_ACHECK_PLAN":                &BUTTON_MAINTENANCE_BULK_ACHECK_PLAN,
+	"BUTTON_FINANCE_MARKETING_NEW_COMPANY":               &BUTTON_FINANCE_MARKETING_NEW_COMPANY,
+	"LIST_FINANCE_MARKETING_COMPANIES":                   &LIST_FINANCE_MARKETING_COMPANIES,
+	"TEXT_MARKETING_COMPANY_NAME":                        &TEXT_MARKETING_COMPANY_NAME,
+	"TEXT_MARKETING_COMPANY_DURATION":                    &TEXT_MARKETING_COMPANY_DURATION,
+	"ELEM_FINANCE_MARKETING_INC_AIRLINE_REP":             &ELEM_FINANCE_MARKETING_INC_AIRLINE_REP,
+	"ELEM_FINANCE_MARKETING_INC_CARGO_REP":               &ELEM_FINANCE_MARKETING_INC_CARGO_REP,
+	"ELEM_FINANCE_MARKETING_ECO_FRIENDLY":                &ELEM_FINANCE_MARKETING_ECO_FRIENDLY,
+	"SELECT_FINANCE_MARKETING_COMPANY_DURATION":          &SELECT_FINANCE_MARKETING_COMPANY_DURATION,
+	"OPTION_FINANCE_MARKETING_INC_AIRLINE_REP_24H_VALUE": &OPTION_FINANCE_MARKETING_INC_AIRLINE_REP_24H_VALUE,
+	"OPTION_FINANCE_MARKETING_INC_CARGO_REP_24H_VALUE":   &OPTION_FINANCE_MARKETING_INC_CARGO_REP_24H_VALUE,
+	"TEXT_FINANCE_MARKETING_INC_AIRLINE_REP_COST":        &TEXT_FINANCE_MARKETING_INC_AIRLINE_REP_COST,
+	"TEXT_FINANCE_MARKETING_INC_CARGO_REP_COST":          &TEXT_FINANCE_MARKETING_INC_CARGO_REP_COST,
+	"TEXT_FINANCE_MARKETING_ECO_FRIENDLY_COST":           &TEXT_FINANCE_MARKETING_ECO_FRIENDLY_COST,
+	"BUTTON_FINANCE_MARKETING_INC_AIRLINE_REP_BUY":       &BUTTON_FINANCE_MARKETING_INC_AIRLINE_REP_BUY,
+	"BUTTON_FINANCE_MARKETING_INC_CARGO_REP_BUY":         &BUTTON_FINANCE_MARKETING_INC_CARGO_REP_BUY,
+	"BUTTON_FINANCE_MARKETING_ECO_FRIENDLY_BUY":          &BUTTON_FINANCE_MARKETING_ECO_FRIENDLY_BUY,
+	"BUTTON_BONUS_DUTY_FREE_TAB":                         &BUTTON_BONUS_DUTY_FREE_TAB,
+	"BUTTON_BONUS_CLAIM_GIFT":                            &BUTTON_BONUS_CLAIM_GIFT,
+}
+
+// builtinSelectors keeps the built-in values of the selectors for restoring them when the override is removed.
+var builtinSelectors = copySelectors()
+
+var (
+	// selectorsMu guards the values of the selectors, the pending overrides and the number of the selectors users.
+	selectorsMu sync.Mutex
+	// selectorsUsers is the number of the runs which use the selectors, see UseSelectors.
+	selectorsUsers int
+	// pendingOverrides are applied when the selectors aren't used by any run.
+	pendingOverrides    map[string]string
+	hasPendingOverrides bool
+)
+
+// copySelectors returns the current values of the selectors.
+func copySelectors() map[string]string {
+	values := make(map[string]string, len(selectors))
+
+	for name, sel := range selectors {
+		values[name] = *sel
+	}
+
+	return values
+}
+
+// SelectorNames returns the sorted names of all CSS selectors.
+func SelectorNames() []string {
+	return slices.Sorted(maps.Keys(selectors))
+}
+
+// Selector returns the current value of the CSS selector by its name.
+func Selector(name string) (string, bool) {
+	selectorsMu.Lock()
+	defer selectorsMu.Unlock()
+
+	return SelectorLocked(name)
+}
+
+// SelectorLocked returns the current value of the CSS selector by its name like Selector,
+// but without locking, so the caller must use the selectors with UseSelectors.
+func SelectorLocked(name string) (string, bool) {
+	sel, ok := selectors[name]
+	if !ok {
+		return "", false
+	}
+
+	return *sel, true
+}
+
+// ApplySelectorOverrides restores the built-in selectors and replaces them with the overrides
+// keyed by the selector names, e.g. "TEXT_FUEL_FUEL_PRICE".
+// It returns the sorted names of the unknown overrides, they are ignored.
+// If the selectors are used by the runs (see UseSelectors), the overrides are applied
+// after the last of them is finished, so the caller never waits for the runs.
+func ApplySelectorOverrides(overrides map[string]string) []string {
+	var unknown []string
+
+	for name := range overrides {
+		if _, ok := selectors[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	slices.Sort(unknown)
+
+	selectorsMu.Lock()
+	defer selectorsMu.Unlock()
+
+	pendingOverrides = overrides
+	hasPendingOverrides = true
+
+	if selectorsUsers == 0 {
+		applyPendingOverrides()
+	}
+
+	return unknown
+}
+
+// applyPendingOverrides replaces the selectors with the pending overrides, the caller must hold selectorsMu.
+func applyPendingOverrides() {
+	if !hasPendingOverrides {
+		return
+	}
+
+	for name, sel := range selectors {
+		*sel = builtinSelectors[name]
+	}
+
+	for name, value := range pendingOverrides {
+		if sel, ok := selectors[name]; ok {
+			*sel = value
+		}
+	}
+
+	pendingOverrides = nil
+	hasPendingOverrides = false
+}
+
+// UseSelectors prevents overriding the selectors while they are used, e.g. during the run.
+// The overrides set in the meantime are postponed until all users release the selectors.
+// It returns the function which releases the selectors.
+func UseSelectors() func() {
+	selectorsMu.Lock()
+	defer selectorsMu.Unlock()
+
+	selectorsUsers++
+
+	return func() {
+		selectorsMu.Lock()
+		defer selectorsMu.Unlock()
+
+		selectorsUsers--
+
+		if selectorsUsers == 0 {
+			applyPendingOverrides()
+		}
+	}
+}
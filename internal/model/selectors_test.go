@@ -0,0 +1,87 @@
+package model
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+)
+
+func TestSelectorsMap(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "css.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parser.ParseFile() returned error: %v", err)
+	}
+
+	declared := 0
+
+	// every selector declared in css.go must be overridable by its name
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				declared++
+
+				if _, ok := selectors[name.Name]; !ok {
+					t.Errorf(`selector '%s' is missing in the selectors map`, name.Name)
+				}
+			}
+		}
+	}
+
+	if declared != len(selectors) {
+		t.Errorf(`selectors map has '%d' selectors, expected '%d'`, len(selectors), declared)
+	}
+}
+
+func TestApplySelectorOverrides(t *testing.T) {
+	t.Cleanup(func() { ApplySelectorOverrides(nil) })
+
+	unknown := ApplySelectorOverrides(map[string]string{
+		"TEXT_FUEL_FUEL_PRICE": "span#newFuelPrice",
+		"TEXT_FUEL_PRICE":      "span#typo",
+	})
+
+	if TEXT_FUEL_FUEL_PRICE != "span#newFuelPrice" {
+		t.Errorf(`TEXT_FUEL_FUEL_PRICE is '%s', expected '%s'`, TEXT_FUEL_FUEL_PRICE, "span#newFuelPrice")
+	}
+
+	if !slices.Equal(unknown, []string{"TEXT_FUEL_PRICE"}) {
+		t.Errorf(`unknown selectors '%+v', expected '%+v'`, unknown, []string{"TEXT_FUEL_PRICE"})
+	}
+
+	// the removed override restores the built-in selector
+	ApplySelectorOverrides(nil)
+
+	if sel, _ := Selector("TEXT_FUEL_FUEL_PRICE"); sel != builtinSelectors["TEXT_FUEL_FUEL_PRICE"] || sel == "span#newFuelPrice" {
+		t.Errorf(`TEXT_FUEL_FUEL_PRICE is '%s' after the override is removed, expected the built-in one`, sel)
+	}
+}
+
+func TestUseSelectors(t *testing.T) {
+	t.Cleanup(func() { ApplySelectorOverrides(nil) })
+
+	release := UseSelectors()
+	secondRelease := UseSelectors()
+
+	// the overrides don't wait for the runs which use the selectors
+	ApplySelectorOverrides(map[string]string{"TEXT_FUEL_FUEL_PRICE": "span#newFuelPrice"})
+
+	for _, releaseFunc := range []func(){release, secondRelease} {
+		if sel, _ := SelectorLocked("TEXT_FUEL_FUEL_PRICE"); sel != builtinSelectors["TEXT_FUEL_FUEL_PRICE"] {
+			t.Errorf(`TEXT_FUEL_FUEL_PRICE is '%s' while the selectors are used, expected the built-in one`, sel)
+		}
+
+		releaseFunc()
+	}
+
+	// the postponed overrides are applied after the last run is finished
+	if sel, _ := Selector("TEXT_FUEL_FUEL_PRICE"); sel != "span#newFuelPrice" {
+		t.Errorf(`TEXT_FUEL_FUEL_PRICE is '%s' after the selectors are released, expected '%s'`, sel, "span#newFuelPrice")
+	}
+}
@@ -0,0 +1,226 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/utils"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// CLICK_DELAY defines how long ClickWait waits for the page to handle the click.
+	CLICK_DELAY time.Duration = 2 * time.Second
+	// SCREENSHOT_QUALITY defines the quality of captured screenshots, 100 means the lossless PNG.
+	SCREENSHOT_QUALITY int = 100
+)
+
+// Chromedp is the Page implementation backed by the Chrome browser.
+// The browser tab is taken from the context passed to every method.
+type Chromedp struct {
+	// ClickDelay defines how long ClickWait waits after the click.
+	ClickDelay time.Duration
+}
+
+// NewChromedp creates a new Chromedp page driver.
+func NewChromedp() *Chromedp {
+	return &Chromedp{
+		ClickDelay: CLICK_DELAY,
+	}
+}
+
+// Navigate opens the URL in the current tab.
+func (p *Chromedp) Navigate(ctx context.Context, url string) error {
+	return chromedp.Run(ctx, chromedp.Navigate(url))
+}
+
+// Reload reloads the current page and waits until the loading overlay is not visible.
+func (p *Chromedp) Reload(ctx context.Context) error {
+	return chromedp.Run(ctx, utils.RefreshPage())
+}
+
+// OpenTab opens the URL in a new tab and activates it.
+func (p *Chromedp) OpenTab(ctx context.Context, url string) (context.Context, context.CancelFunc, error) {
+	tabCtx, cancel := chromedp.NewContext(ctx)
+
+	if err := chromedp.Run(tabCtx,
+		chromedp.Navigate(url),
+	); err != nil {
+		cancel()
+
+		return nil, nil, err
+	}
+
+	// activate the new tab target, otherwise the page isn't rendered in the headless mode
+	c := chromedp.FromContext(tabCtx)
+
+	if err := chromedp.Run(tabCtx,
+		target.ActivateTarget(c.Target.TargetID),
+	); err != nil {
+		cancel()
+
+		return nil, nil, err
+	}
+
+	return tabCtx, cancel, nil
+}
+
+// Click sends a mouse click to the first element matching the selector.
+func (p *Chromedp) Click(ctx context.Context, sel string) error {
+	return chromedp.Run(ctx, chromedp.Click(sel, chromedp.ByQuery))
+}
+
+// ClickWait sends a mouse click to the first element matching the selector and waits for the click delay.
+func (p *Chromedp) ClickWait(ctx context.Context, sel string) error {
+	return chromedp.Run(ctx,
+		chromedp.Click(sel, chromedp.ByQuery),
+		chromedp.Sleep(p.ClickDelay),
+	)
+}
+
+// Text returns the visible text of the first element matching the selector.
+func (p *Chromedp) Text(ctx context.Context, sel string) (string, error) {
+	var text string
+
+	err := chromedp.Run(ctx, chromedp.Text(sel, &text, chromedp.ByQuery))
+
+	return text, err
+}
+
+// Float returns the float value parsed from the text of the first element matching the selector.
+func (p *Chromedp) Float(ctx context.Context, sel string) (float64, error) {
+	var value float64
+
+	err := chromedp.Run(ctx, utils.GetFloatFromElement(sel, &value))
+
+	return value, err
+}
+
+// Int returns the Integer value parsed from the text of the first element matching the selector.
+func (p *Chromedp) Int(ctx context.Context, sel string) (int, error) {
+	var value int
+
+	err := chromedp.Run(ctx, utils.GetIntFromElement(sel, &value))
+
+	return value, err
+}
+
+// Attributes returns attributes of the first element matching the selector.
+func (p *Chromedp) Attributes(ctx context.Context, sel string) (map[string]string, error) {
+	var attributes map[string]string
+
+	err := chromedp.Run(ctx, chromedp.Attributes(sel, &attributes, chromedp.ByQuery))
+
+	return attributes, err
+}
+
+// Nodes returns all elements matching the selector.
+func (p *Chromedp) Nodes(ctx context.Context, sel string) ([]Node, error) {
+	var cdpNodes []*cdp.Node
+
+	if err := chromedp.Run(ctx, chromedp.Nodes(sel, &cdpNodes, chromedp.ByQueryAll)); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]Node, 0, len(cdpNodes))
+
+	for _, cdpNode := range cdpNodes {
+		nodes = append(nodes, &chromedpNode{node: cdpNode})
+	}
+
+	return nodes, nil
+}
+
+// SetValue sets the value of the first input or select element matching the selector.
+func (p *Chromedp) SetValue(ctx context.Context, sel, value string) error {
+	return chromedp.Run(ctx, chromedp.SetValue(sel, value, chromedp.ByQuery))
+}
+
+// SendKeys types the value into the first element matching the selector.
+func (p *Chromedp) SendKeys(ctx context.Context, sel, value string) error {
+	return chromedp.Run(ctx, chromedp.SendKeys(sel, value, chromedp.ByQuery))
+}
+
+// SetAttribute sets the attribute value of the first element matching the selector.
+func (p *Chromedp) SetAttribute(ctx context.Context, sel, name, value string) error {
+	return chromedp.Run(ctx, chromedp.SetAttributeValue(sel, name, value, chromedp.ByQuery))
+}
+
+// WaitReady waits until the element matching the selector is ready.
+func (p *Chromedp) WaitReady(ctx context.Context, sel string) error {
+	return chromedp.Run(ctx, chromedp.WaitReady(sel, chromedp.ByQuery))
+}
+
+// WaitNotVisible waits until the element matching the selector is not visible.
+func (p *Chromedp) WaitNotVisible(ctx context.Context, sel string) error {
+	return chromedp.Run(ctx, chromedp.WaitNotVisible(sel, chromedp.ByQuery))
+}
+
+// Visible checks if the element matching the selector becomes visible during the timeout.
+func (p *Chromedp) Visible(ctx context.Context, sel string, timeout time.Duration) bool {
+	return utils.IsElementVisible(ctx, sel, int(timeout.Seconds()))
+}
+
+// Capture returns the current URL, the full-page screenshot and the outer HTML of the first element
+// matching the selector or of the whole page. It doesn't wait for the element.
+func (p *Chromedp) Capture(ctx context.Context, sel string) (Capture, error) {
+	var c Capture
+
+	err := chromedp.Run(ctx,
+		chromedp.Location(&c.URL),
+		chromedp.Evaluate(fmt.Sprintf(`(document.querySelector(%q) || document.documentElement).outerHTML`, sel), &c.HTML),
+		chromedp.FullScreenshot(&c.Screenshot, SCREENSHOT_QUALITY),
+	)
+
+	return c, err
+}
+
+// chromedpNode is the Node implementation backed by the Chrome DOM node.
+type chromedpNode struct {
+	node *cdp.Node
+}
+
+// Attribute returns the node's attribute value.
+func (n *chromedpNode) Attribute(name string) string {
+	return n.node.AttributeValue(name)
+}
+
+// Click sends a mouse click to the first child element matching the selector.
+func (n *chromedpNode) Click(ctx context.Context, sel string) error {
+	return chromedp.Run(ctx, chromedp.Click(sel, chromedp.ByQuery, chromedp.FromNode(n.node)))
+}
+
+// Text returns the visible text of the first child element matching the selector.
+func (n *chromedpNode) Text(ctx context.Context, sel string) (string, error) {
+	var text string
+
+	err := chromedp.Run(ctx, chromedp.Text(sel, &text, chromedp.ByQuery, chromedp.FromNode(n.node)))
+
+	return text, err
+}
+
+// Float returns the float value parsed from the text of the first child element matching the selector.
+func (n *chromedpNode) Float(ctx context.Context, sel string) (float64, error) {
+	var value float64
+
+	err := chromedp.Run(ctx, utils.GetFloatFromChildElement(sel, &value, n.node))
+
+	return value, err
+}
+
+// Int returns the Integer value parsed from the text of the first child element matching the selector.
+func (n *chromedpNode) Int(ctx context.Context, sel string) (int, error) {
+	var value int
+
+	err := chromedp.Run(ctx, utils.GetIntFromChildElement(sel, &value, n.node))
+
+	return value, err
+}
+
+// Visible checks if any child element matches the selector.
+func (n *chromedpNode) Visible(ctx context.Context, sel string) bool {
+	return utils.IsSubElementVisible(ctx, sel, n.node)
+}
@@ -0,0 +1,358 @@
+package page
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ashokhin/am4bot/internal/utils"
+)
+
+// ErrNotFound is returned by the Fake page when no element matches the selector.
+var ErrNotFound = errors.New("element not found")
+
+// Fake is the in-memory Page implementation for testing the bot logic without a browser.
+// Elements are described by the maps of selectors, and all actions are recorded.
+type Fake struct {
+	// Texts holds texts of the elements by their selectors.
+	Texts map[string]string
+	// Attrs holds attributes of the elements by their selectors.
+	Attrs map[string]map[string]string
+	// Lists holds nodes returned by Nodes by their selectors.
+	Lists map[string][]*FakeNode
+	// Visibility overrides the visibility of the elements by their selectors.
+	// Elements without an override are visible if they are present in Texts, Attrs or Lists.
+	Visibility map[string]bool
+	// Errors holds errors returned for any action on the elements by their selectors.
+	Errors map[string]error
+	// OnClick holds callbacks executed after the click on the elements by their selectors.
+	// Callbacks may change the page state, e.g. switch tabs.
+	OnClick map[string]func(f *Fake)
+
+	// Clicks records selectors of all clicked elements.
+	Clicks []string
+	// Values records the last values set or typed into the elements by their selectors.
+	Values map[string]string
+	// URLs records all navigated URLs.
+	URLs []string
+
+	mu sync.Mutex
+}
+
+// NewFake creates a new empty Fake page.
+func NewFake() *Fake {
+	return &Fake{
+		Texts:      make(map[string]string),
+		Attrs:      make(map[string]map[string]string),
+		Lists:      make(map[string][]*FakeNode),
+		Visibility: make(map[string]bool),
+		Errors:     make(map[string]error),
+		OnClick:    make(map[string]func(f *Fake)),
+		Values:     make(map[string]string),
+	}
+}
+
+// Clicked returns how many times the element matching the selector has been clicked.
+func (f *Fake) Clicked(sel string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+
+	for _, clicked := range f.Clicks {
+		if clicked == sel {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Navigate records the URL.
+func (f *Fake) Navigate(ctx context.Context, url string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.URLs = append(f.URLs, url)
+
+	return nil
+}
+
+// Reload does nothing.
+func (f *Fake) Reload(ctx context.Context) error {
+	return nil
+}
+
+// OpenTab records the URL and returns the same context, so the same fake page is used by the new tab.
+func (f *Fake) OpenTab(ctx context.Context, url string) (context.Context, context.CancelFunc, error) {
+	if err := f.Navigate(ctx, url); err != nil {
+		return nil, nil, err
+	}
+
+	return ctx, func() {}, nil
+}
+
+// Click records the click and executes the OnClick callback of the element.
+func (f *Fake) Click(ctx context.Context, sel string) error {
+	f.mu.Lock()
+
+	if err := f.Errors[sel]; err != nil {
+		f.mu.Unlock()
+
+		return err
+	}
+
+	f.Clicks = append(f.Clicks, sel)
+	onClick := f.OnClick[sel]
+
+	f.mu.Unlock()
+
+	if onClick != nil {
+		onClick(f)
+	}
+
+	return nil
+}
+
+// ClickWait works the same way as Click without waiting.
+func (f *Fake) ClickWait(ctx context.Context, sel string) error {
+	return f.Click(ctx, sel)
+}
+
+// Text returns the text of the element.
+func (f *Fake) Text(ctx context.Context, sel string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors[sel]; err != nil {
+		return "", err
+	}
+
+	text, ok := f.Texts[sel]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, sel)
+	}
+
+	return text, nil
+}
+
+// Float returns the float value parsed from the text of the element.
+func (f *Fake) Float(ctx context.Context, sel string) (float64, error) {
+	text, err := f.Text(ctx, sel)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.ParseFloat(text)
+}
+
+// Int returns the Integer value parsed from the text of the element.
+func (f *Fake) Int(ctx context.Context, sel string) (int, error) {
+	text, err := f.Text(ctx, sel)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.ParseInt(text)
+}
+
+// Attributes returns attributes of the element.
+func (f *Fake) Attributes(ctx context.Context, sel string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors[sel]; err != nil {
+		return nil, err
+	}
+
+	attributes, ok := f.Attrs[sel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, sel)
+	}
+
+	return attributes, nil
+}
+
+// Nodes returns the list of nodes for the selector.
+func (f *Fake) Nodes(ctx context.Context, sel string) ([]Node, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors[sel]; err != nil {
+		return nil, err
+	}
+
+	fakeNodes, ok := f.Lists[sel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, sel)
+	}
+
+	nodes := make([]Node, 0, len(fakeNodes))
+
+	for _, n := range fakeNodes {
+		nodes = append(nodes, n)
+	}
+
+	return nodes, nil
+}
+
+// SetValue records the value of the element.
+func (f *Fake) SetValue(ctx context.Context, sel, value string) error {
+	return f.setValue(sel, value)
+}
+
+// SendKeys records the value of the element.
+func (f *Fake) SendKeys(ctx context.Context, sel, value string) error {
+	return f.setValue(sel, value)
+}
+
+// SetAttribute sets the attribute value of the element.
+func (f *Fake) SetAttribute(ctx context.Context, sel, name, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors[sel]; err != nil {
+		return err
+	}
+
+	if f.Attrs[sel] == nil {
+		f.Attrs[sel] = make(map[string]string)
+	}
+
+	f.Attrs[sel][name] = value
+
+	return nil
+}
+
+// WaitReady returns the error of the element if any.
+func (f *Fake) WaitReady(ctx context.Context, sel string) error {
+	return f.err(sel)
+}
+
+// WaitNotVisible returns the error of the element if any.
+func (f *Fake) WaitNotVisible(ctx context.Context, sel string) error {
+	return f.err(sel)
+}
+
+// Visible returns the visibility of the element.
+func (f *Fake) Visible(ctx context.Context, sel string, timeout time.Duration) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if visible, ok := f.Visibility[sel]; ok {
+		return visible
+	}
+
+	_, hasText := f.Texts[sel]
+	_, hasAttrs := f.Attrs[sel]
+	_, hasList := f.Lists[sel]
+
+	return hasText || hasAttrs || hasList
+}
+
+// Capture returns the last navigated URL and the text of the element as its HTML.
+func (f *Fake) Capture(ctx context.Context, sel string) (Capture, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors[sel]; err != nil {
+		return Capture{}, err
+	}
+
+	c := Capture{HTML: f.Texts[sel]}
+
+	if len(f.URLs) > 0 {
+		c.URL = f.URLs[len(f.URLs)-1]
+	}
+
+	return c, nil
+}
+
+// setValue records the value of the element.
+func (f *Fake) setValue(sel, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors[sel]; err != nil {
+		return err
+	}
+
+	f.Values[sel] = value
+
+	return nil
+}
+
+// err returns the error of the element if any.
+func (f *Fake) err(sel string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.Errors[sel]
+}
+
+// FakeNode is the in-memory Node implementation used by the Fake page.
+type FakeNode struct {
+	// Attrs holds the node's attributes.
+	Attrs map[string]string
+	// Texts holds texts of the child elements by their selectors.
+	Texts map[string]string
+	// Clicks records selectors of all clicked child elements.
+	Clicks []string
+	// OnClick holds callbacks executed after the click on the child elements by their selectors.
+	OnClick map[string]func()
+}
+
+// Attribute returns the node's attribute value.
+func (n *FakeNode) Attribute(name string) string {
+	return n.Attrs[name]
+}
+
+// Click records the click and executes the OnClick callback of the child element.
+func (n *FakeNode) Click(ctx context.Context, sel string) error {
+	n.Clicks = append(n.Clicks, sel)
+
+	if onClick := n.OnClick[sel]; onClick != nil {
+		onClick()
+	}
+
+	return nil
+}
+
+// Text returns the text of the child element.
+func (n *FakeNode) Text(ctx context.Context, sel string) (string, error) {
+	text, ok := n.Texts[sel]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, sel)
+	}
+
+	return text, nil
+}
+
+// Float returns the float value parsed from the text of the child element.
+func (n *FakeNode) Float(ctx context.Context, sel string) (float64, error) {
+	text, err := n.Text(ctx, sel)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.ParseFloat(text)
+}
+
+// Int returns the Integer value parsed from the text of the child element.
+func (n *FakeNode) Int(ctx context.Context, sel string) (int, error) {
+	text, err := n.Text(ctx, sel)
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.ParseInt(text)
+}
+
+// Visible checks if the child element is present.
+func (n *FakeNode) Visible(ctx context.Context, sel string) bool {
+	_, ok := n.Texts[sel]
+
+	return ok
+}
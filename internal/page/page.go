@@ -0,0 +1,107 @@
+package page
+
+import (
+	"context"
+	"time"
+)
+
+// Page is a browser-agnostic driver of the Airline Manager web page.
+// All selectors are CSS selectors from the model package.
+type Page interface {
+	// Navigate opens the URL in the current tab.
+	Navigate(ctx context.Context, url string) error
+	// Reload reloads the current page and waits until it's loaded.
+	Reload(ctx context.Context) error
+	// OpenTab opens the URL in a new tab and returns the context bound to that tab.
+	OpenTab(ctx context.Context, url string) (context.Context, context.CancelFunc, error)
+	// Click sends a mouse click to the first element matching the selector.
+	Click(ctx context.Context, sel string) error
+	// ClickWait sends a mouse click to the first element matching the selector
+	// and waits until the page handles it.
+	ClickWait(ctx context.Context, sel string) error
+	// Text returns the visible text of the first element matching the selector.
+	Text(ctx context.Context, sel string) (string, error)
+	// Float returns the float value parsed from the text of the first element matching the selector.
+	Float(ctx context.Context, sel string) (float64, error)
+	// Int returns the Integer value parsed from the text of the first element matching the selector.
+	Int(ctx context.Context, sel string) (int, error)
+	// Attributes returns attributes of the first element matching the selector.
+	Attributes(ctx context.Context, sel string) (map[string]string, error)
+	// Nodes returns all elements matching the selector.
+	Nodes(ctx context.Context, sel string) ([]Node, error)
+	// SetValue sets the value of the first input or select element matching the selector.
+	SetValue(ctx context.Context, sel, value string) error
+	// SendKeys types the value into the first element matching the selector.
+	SendKeys(ctx context.Context, sel, value string) error
+	// SetAttribute sets the attribute value of the first element matching the selector.
+	SetAttribute(ctx context.Context, sel, name, value string) error
+	// WaitReady waits until the element matching the selector is ready.
+	WaitReady(ctx context.Context, sel string) error
+	// WaitNotVisible waits until the element matching the selector is not visible.
+	WaitNotVisible(ctx context.Context, sel string) error
+	// Visible checks if the element matching the selector becomes visible during the timeout.
+	Visible(ctx context.Context, sel string, timeout time.Duration) bool
+	// Capture returns the current URL, the full-page screenshot and the outer HTML of the first element
+	// matching the selector (or of the whole page if nothing matches) for debugging.
+	Capture(ctx context.Context, sel string) (Capture, error)
+}
+
+// Capture is the state of the page captured for debugging.
+type Capture struct {
+	URL        string
+	Screenshot []byte
+	HTML       string
+}
+
+// Node is a single element of the page returned by Page.Nodes.
+// All selectors are relative to the node.
+type Node interface {
+	// Attribute returns the node's attribute value or empty string if the attribute is missing.
+	Attribute(name string) string
+	// Click sends a mouse click to the first child element matching the selector.
+	Click(ctx context.Context, sel string) error
+	// Text returns the visible text of the first child element matching the selector.
+	Text(ctx context.Context, sel string) (string, error)
+	// Float returns the float value parsed from the text of the first child element matching the selector.
+	Float(ctx context.Context, sel string) (float64, error)
+	// Int returns the Integer value parsed from the text of the first child element matching the selector.
+	Int(ctx context.Context, sel string) (int, error)
+	// Visible checks if any child element matches the selector.
+	Visible(ctx context.Context, sel string) bool
+}
+
+// FloatField binds the selector of an element to the variable for its float value.
+type FloatField struct {
+	Selector string
+	Value    *float64
+}
+
+// ReadFloats reads float values of all fields from the page.
+// It stops on the first error.
+func ReadFloats(ctx context.Context, p Page, fields ...FloatField) error {
+	for _, field := range fields {
+		value, err := p.Float(ctx, field.Selector)
+		if err != nil {
+			return err
+		}
+
+		*field.Value = value
+	}
+
+	return nil
+}
+
+// ReadChildFloats reads float values of all fields from the node's child elements.
+// It stops on the first error.
+func ReadChildFloats(ctx context.Context, n Node, fields ...FloatField) error {
+	for _, field := range fields {
+		value, err := n.Float(ctx, field.Selector)
+		if err != nil {
+			return err
+		}
+
+		*field.Value = value
+	}
+
+	return nil
+}
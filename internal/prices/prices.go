@@ -0,0 +1,208 @@
+package prices
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PRICE_PERIOD defines how long the game keeps the same market price, prices rotate every half hour.
+const PRICE_PERIOD time.Duration = 30 * time.Minute
+
+// Observation represents the market price of the item seen by the bot.
+type Observation struct {
+	Timestamp time.Time `json:"timestamp"`
+	Account   string    `json:"account,omitempty"`
+	Item      string    `json:"item"`
+	Price     float64   `json:"price"`
+}
+
+// period returns the start of the price period of the observation.
+func (o Observation) period() time.Time {
+	return o.Timestamp.Truncate(PRICE_PERIOD)
+}
+
+// History is an append-only JSON Lines file with the observed market prices.
+// Only the first observation of the item in the price period is recorded.
+type History struct {
+	filePath string
+	mu       sync.Mutex
+	// lastPeriods holds the price period of the last recorded observation of every item
+	lastPeriods map[string]time.Time
+}
+
+// New creates a new History instance for the specified file path.
+func New(filePath string) *History {
+	return &History{
+		filePath:    filePath,
+		lastPeriods: make(map[string]time.Time),
+	}
+}
+
+// FilePath returns the path of the history file.
+func (h *History) FilePath() string {
+	return h.filePath
+}
+
+// Record writes the observation to the end of the history file
+// if the price of the item isn't recorded in the same price period yet.
+// It returns false if the observation is skipped.
+func (h *History) Record(o Observation) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.lastPeriods[o.Item].Equal(o.period()) {
+		return false, nil
+	}
+
+	line, err := json.Marshal(o)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.OpenFile(h.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, err
+	}
+
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return false, err
+	}
+
+	h.lastPeriods[o.Item] = o.period()
+
+	slog.Debug("price observation recorded", "file", h.filePath, "observation", o)
+
+	return true, nil
+}
+
+// Read returns the observations of the item which have been recorded since the specified time,
+// one per price period. Missing history file is treated as an empty history.
+func (h *History) Read(item string, since time.Time) ([]Observation, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.Open(h.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var observations []Observation
+
+	// accounts could share the file and the restarted bot records the price of the period again
+	periods := make(map[time.Time]bool)
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		var o Observation
+
+		lineNumber++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &o); err != nil {
+			slog.Warn("skip malformed price observation", "file", h.filePath, "line", lineNumber, "error", err)
+
+			continue
+		}
+
+		if o.Item != item || o.Timestamp.Before(since) || periods[o.period()] {
+			continue
+		}
+
+		periods[o.period()] = true
+		observations = append(observations, o)
+	}
+
+	return observations, scanner.Err()
+}
+
+// Percentile returns the price below which the percent of the observed prices falls (the nearest-rank method),
+// e.g. the 20th percentile is the highest price of the cheapest 20% of observations.
+// It returns false if there are no observations.
+func Percentile(observations []Observation, percent float64) (float64, bool) {
+	if len(observations) == 0 {
+		return 0, false
+	}
+
+	values := make([]float64, 0, len(observations))
+
+	for _, o := range observations {
+		values = append(values, o.Price)
+	}
+
+	slices.Sort(values)
+
+	rank := max(int(math.Ceil(percent/100*float64(len(values)))), 1)
+
+	return values[rank-1], true
+}
+
+// ExpectedWait returns the average time from the moment the price gets higher than the maximal price
+// until the next price which isn't higher, i.e. how long the bot waits for the next cheap price.
+// Only the finished streaks are averaged, the time already spent in the current streak isn't taken into account,
+// so during the streak longer than usual the wait is underestimated.
+// It returns false if there are no cheap prices in the observations.
+func ExpectedWait(observations []Observation, maxPrice float64) (time.Duration, bool) {
+	sorted := slices.Clone(observations)
+	slices.SortFunc(sorted, func(a, b Observation) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	var (
+		waits       []time.Duration
+		streakStart time.Time
+		inStreak    bool
+		seenCheap   bool
+	)
+
+	for _, o := range sorted {
+		if o.Price > maxPrice {
+			if !inStreak {
+				streakStart = o.period()
+				inStreak = true
+			}
+
+			continue
+		}
+
+		// the first expensive streak could start before the observations
+		if inStreak && seenCheap {
+			waits = append(waits, o.period().Sub(streakStart))
+		}
+
+		inStreak = false
+		seenCheap = true
+	}
+
+	if !seenCheap {
+		return 0, false
+	}
+
+	var total time.Duration
+
+	for _, wait := range waits {
+		total += wait
+	}
+
+	if len(waits) == 0 {
+		return 0, true
+	}
+
+	return total / time.Duration(len(waits)), true
+}
@@ -0,0 +1,122 @@
+package prices
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecordRead(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "prices.jsonl"))
+	now := time.Now().UTC().Truncate(PRICE_PERIOD)
+
+	observations := []Observation{
+		{Timestamp: now.Add(-48 * time.Hour), Item: "fuel", Price: 500},
+		{Timestamp: now, Item: "fuel", Price: 800},
+		{Timestamp: now.Add(5 * time.Minute), Item: "fuel", Price: 800},
+		{Timestamp: now.Add(5 * time.Minute), Item: "co2", Price: 120},
+	}
+	expectedRecorded := []bool{true, true, false, true}
+
+	for i, o := range observations {
+		recorded, err := h.Record(o)
+		if err != nil {
+			t.Fatalf(`Record(%+v) returned unexpected error: %+v`, o, err)
+		}
+
+		if recorded != expectedRecorded[i] {
+			t.Errorf(`Record(%+v) returned '%t', expected '%t'`, o, recorded, expectedRecorded[i])
+		}
+	}
+
+	// the restarted bot records the price of the same period again
+	if _, err := New(h.FilePath()).Record(observations[2]); err != nil {
+		t.Fatalf(`Record(%+v) returned unexpected error: %+v`, observations[2], err)
+	}
+
+	result, err := h.Read("fuel", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf(`Read() returned unexpected error: %+v`, err)
+	}
+
+	if !slices.Equal(result, observations[1:2]) {
+		t.Errorf(`Read() returned '%+v', expected '%+v'`, result, observations[1:2])
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	result, err := New(filepath.Join(t.TempDir(), "missing.jsonl")).Read("fuel", time.Time{})
+	if err != nil || len(result) != 0 {
+		t.Errorf(`Read() returned '%+v', '%+v', expected empty result without error`, result, err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	testCases := map[string]struct {
+		prices        []float64
+		percent       float64
+		expectedPrice float64
+		expectedOk    bool
+	}{
+		"test01": {nil, 20, 0, false},
+		"test02": {[]float64{900}, 20, 900, true},
+		"test03": {[]float64{900, 400, 1200, 500, 700, 600, 1000, 800, 300, 1100}, 20, 400, true},
+		"test04": {[]float64{900, 400, 1200, 500, 700, 600, 1000, 800, 300, 1100}, 25, 500, true},
+		"test05": {[]float64{900, 400, 1200, 500, 700, 600, 1000, 800, 300, 1100}, 0, 300, true},
+		"test06": {[]float64{900, 400, 1200, 500, 700, 600, 1000, 800, 300, 1100}, 100, 1200, true},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			var observations []Observation
+
+			for _, price := range testData.prices {
+				observations = append(observations, Observation{Item: "fuel", Price: price})
+			}
+
+			price, ok := Percentile(observations, testData.percent)
+
+			if price != testData.expectedPrice || ok != testData.expectedOk {
+				t.Errorf(`Percentile(%v, %v) returned '%v', '%t', expected '%v', '%t'`,
+					testData.prices, testData.percent, price, ok, testData.expectedPrice, testData.expectedOk)
+			}
+		})
+	}
+}
+
+func TestExpectedWait(t *testing.T) {
+	testCases := map[string]struct {
+		prices       []float64
+		expectedWait time.Duration
+		expectedOk   bool
+	}{
+		"test01": {nil, 0, false},
+		"test02": {[]float64{900, 800, 700}, 0, false},
+		"test03": {[]float64{400, 500}, 0, true},
+		// the first streak has no cheap price before it, streaks of 2 and 4 periods
+		"test04": {[]float64{900, 400, 900, 800, 400, 700, 700, 900, 800, 500}, 90 * time.Minute, true},
+		// the current streak of 6 periods is longer than the finished one of 2 periods, only the finished streak is averaged
+		"test05": {[]float64{400, 900, 800, 400, 900, 900, 700, 800, 900, 900}, 60 * time.Minute, true},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			var observations []Observation
+
+			start := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+			// observations are not ordered in the file of several accounts
+			for i, price := range slices.Backward(testData.prices) {
+				observations = append(observations, Observation{Timestamp: start.Add(time.Duration(i) * PRICE_PERIOD), Item: "fuel", Price: price})
+			}
+
+			wait, ok := ExpectedWait(observations, 600)
+
+			if wait != testData.expectedWait || ok != testData.expectedOk {
+				t.Errorf(`ExpectedWait(%v) returned '%v', '%t', expected '%v', '%t'`,
+					testData.prices, wait, ok, testData.expectedWait, testData.expectedOk)
+			}
+		})
+	}
+}
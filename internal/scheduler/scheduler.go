@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// COALESCE_DELAY defines how long the scheduler waits after the first due service
+// to collect the other services which are due at the same tick into one run.
+const COALESCE_DELAY time.Duration = 2 * time.Second
+
+// RunFunc executes the list of services within a single bot session.
+type RunFunc func(ctx context.Context, services []string)
+
+// Scheduler runs services on their own cron schedules.
+// Services which are due at the same time are coalesced into a single run,
+// and runs never overlap each other.
+type Scheduler struct {
+	cron          *cron.Cron
+	entries       []cron.EntryID
+	run           RunFunc
+	coalesceDelay time.Duration
+	mu            sync.Mutex
+	pending       []string
+	paused        bool
+	wakeup        chan struct{}
+	quit          chan struct{}
+	stopOnce      sync.Once
+	done          chan struct{}
+}
+
+// New creates a new Scheduler which executes due services with the provided function.
+func New(run RunFunc) *Scheduler {
+	return &Scheduler{
+		cron:          cron.New(),
+		run:           run,
+		coalesceDelay: COALESCE_DELAY,
+		wakeup:        make(chan struct{}, 1),
+		quit:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Schedule registers a cron entry for every schedule in the map of cron specs to services.
+// Entries of the previous call are replaced, so the changed schedules are applied to the running scheduler.
+// The previous entries are kept if any schedule is invalid.
+func (s *Scheduler) Schedule(schedules map[string][]string) error {
+	for spec, services := range schedules {
+		if _, err := cron.ParseStandard(spec); err != nil {
+			return fmt.Errorf("invalid schedule %q for services %v: %w", spec, services, err)
+		}
+	}
+
+	for _, id := range s.entries {
+		s.cron.Remove(id)
+	}
+
+	s.entries = nil
+
+	for spec, services := range schedules {
+		slog.Debug("schedule services", "schedule", spec, "services", services)
+
+		id, err := s.cron.AddFunc(spec, func() {
+			if s.Paused() {
+				slog.Debug("scheduler is paused, skip services", "schedule", spec, "services", services)
+
+				return
+			}
+
+			s.Enqueue(services...)
+		})
+		if err != nil {
+			return fmt.Errorf("invalid schedule %q for services %v: %w", spec, services, err)
+		}
+
+		s.entries = append(s.entries, id)
+	}
+
+	return nil
+}
+
+// Enqueue adds services to the next run.
+func (s *Scheduler) Enqueue(services ...string) {
+	s.mu.Lock()
+
+	for _, serviceName := range services {
+		if !slices.Contains(s.pending, serviceName) {
+			s.pending = append(s.pending, serviceName)
+		}
+	}
+
+	s.mu.Unlock()
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Pause stops enqueuing services on their cron schedules until Resume is called.
+// Services enqueued directly with Enqueue are still executed.
+func (s *Scheduler) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused = true
+}
+
+// Resume continues enqueuing services on their cron schedules.
+func (s *Scheduler) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused = false
+}
+
+// Paused reports whether the scheduled runs are paused.
+func (s *Scheduler) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paused
+}
+
+// Start starts the cron scheduler and the runs loop.
+// The loop is stopped when the context is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.cron.Start()
+
+	go s.loop(ctx)
+}
+
+// Stop stops the cron scheduler, so no new runs are planned,
+// and the runs loop after the current run is finished. Pending services are dropped.
+// Use Done to wait for the current run.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+	})
+
+	<-s.cron.Stop().Done()
+}
+
+// Done returns a channel which is closed when the runs loop exits.
+func (s *Scheduler) Done() <-chan struct{} {
+	return s.done
+}
+
+// NextRun returns the nearest time when any of the scheduled services is due.
+func (s *Scheduler) NextRun() time.Time {
+	var next time.Time
+
+	for _, entry := range s.cron.Entries() {
+		if next.IsZero() || entry.Next.Before(next) {
+			next = entry.Next
+		}
+	}
+
+	return next
+}
+
+// loop waits for due services and executes them one run at a time.
+// It exits when the context is cancelled or the scheduler is stopped.
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.quit:
+			return
+		case <-s.wakeup:
+		}
+
+		// let the other services which are due at the same tick join this run
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.quit:
+			return
+		case <-time.After(s.coalesceDelay):
+		}
+
+		services := s.takePending()
+		if len(services) == 0 {
+			continue
+		}
+
+		s.run(ctx, services)
+	}
+}
+
+// takePending returns pending services and clears the pending list.
+func (s *Scheduler) takePending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	services := s.pending
+	s.pending = nil
+
+	return services
+}
@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEnqueueCoalesce(t *testing.T) {
+	runs := make(chan []string, 10)
+
+	s := New(func(ctx context.Context, services []string) {
+		runs <- services
+	})
+	s.coalesceDelay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Start(ctx)
+	defer s.Stop()
+
+	s.Enqueue("depart", "buy_fuel")
+	s.Enqueue("alliance_stats", "depart")
+
+	select {
+	case services := <-runs:
+		expected := []string{"depart", "buy_fuel", "alliance_stats"}
+		if !slices.Equal(services, expected) {
+			t.Errorf(`run services '%+v', expected '%+v'`, services, expected)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("services were not run")
+	}
+
+	select {
+	case services := <-runs:
+		t.Errorf(`unexpected run with services '%+v'`, services)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	testCases := map[string]struct {
+		schedules map[string][]string
+		isValid   bool
+	}{
+		"test01": {map[string][]string{"*/5 * * * *": {"depart"}, "0 * * * *": {"alliance_stats"}}, true},
+		"test02": {map[string][]string{"@daily": {"claim_rewards"}}, true},
+		"test03": {map[string][]string{"every five minutes": {"depart"}}, false},
+		"test04": {map[string][]string{"*/5 * * *": {"depart"}}, false},
+	}
+
+	for testName, testData := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			s := New(func(ctx context.Context, services []string) {})
+
+			err := s.Schedule(testData.schedules)
+			if (err == nil) != testData.isValid {
+				t.Errorf(`Schedule(%+v) returned error '%+v', expected valid: %+v`, testData.schedules, err, testData.isValid)
+			}
+		})
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	s := New(func(ctx context.Context, services []string) {})
+
+	if err := s.Schedule(map[string][]string{"@every 1s": {"depart"}, "@every 2s": {"buy_fuel"}}); err != nil {
+		t.Fatalf("Schedule() returned error: %v", err)
+	}
+
+	if err := s.Schedule(map[string][]string{"@every 1h": {"depart", "buy_fuel"}}); err != nil {
+		t.Fatalf("Schedule() returned error: %v", err)
+	}
+
+	// the invalid schedule doesn't remove the current entries
+	if err := s.Schedule(map[string][]string{"@every 1m": {"depart"}, "*/5 * * *": {"buy_fuel"}}); err == nil {
+		t.Errorf("Schedule() with the invalid schedule returned no error")
+	}
+
+	s.Start(t.Context())
+	defer s.Stop()
+
+	if entries := s.cron.Entries(); len(entries) != 1 {
+		t.Errorf(`scheduler has '%d' entries, expected '%d'`, len(entries), 1)
+	}
+
+	if nextRun := time.Until(s.NextRun()); nextRun < 59*time.Minute {
+		t.Errorf(`next run in '%v', expected in 1h`, nextRun)
+	}
+}
+
+func TestPause(t *testing.T) {
+	runs := make(chan []string, 10)
+
+	s := New(func(ctx context.Context, services []string) {
+		runs <- services
+	})
+	s.coalesceDelay = 10 * time.Millisecond
+
+	if err := s.Schedule(map[string][]string{"@every 1s": {"depart"}}); err != nil {
+		t.Fatalf("Schedule() returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Pause()
+	s.Start(ctx)
+	defer s.Stop()
+
+	select {
+	case services := <-runs:
+		t.Fatalf(`unexpected run with services '%+v' while paused`, services)
+	case <-time.After(1500 * time.Millisecond):
+	}
+
+	// manual runs are executed even while paused
+	s.Enqueue("buy_fuel")
+
+	select {
+	case services := <-runs:
+		if !slices.Equal(services, []string{"buy_fuel"}) {
+			t.Errorf(`run services '%+v', expected '%+v'`, services, []string{"buy_fuel"})
+		}
+	case <-time.After(time.Second):
+		t.Fatal("enqueued services were not run while paused")
+	}
+
+	s.Resume()
+
+	select {
+	case services := <-runs:
+		if !slices.Equal(services, []string{"depart"}) {
+			t.Errorf(`run services '%+v', expected '%+v'`, services, []string{"depart"})
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("scheduled services were not run after resume")
+	}
+}
+
+func TestStopWaitsForRun(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runs := make(chan []string, 10)
+
+	s := New(func(ctx context.Context, services []string) {
+		runs <- services
+		close(started)
+		<-release
+	})
+	s.coalesceDelay = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Start(ctx)
+	s.Enqueue("depart")
+	<-started
+
+	// services enqueued during the stop are dropped
+	s.Enqueue("buy_fuel")
+	s.Stop()
+
+	select {
+	case <-s.Done():
+		t.Fatal("runs loop exited before the current run is finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("runs loop didn't exit after the current run")
+	}
+
+	if len(runs) != 1 {
+		t.Errorf(`scheduler made '%d' runs, expected only one`, len(runs))
+	}
+}
@@ -80,6 +80,16 @@ func floatFromString(str string) (float64, error) {
 	return floatValue, nil
 }
 
+// ParseInt extracts the Integer value from the text of a web element.
+func ParseInt(str string) (int, error) {
+	return intFromString(str)
+}
+
+// ParseFloat extracts the float value from the text of a web element.
+func ParseFloat(str string) (float64, error) {
+	return floatFromString(str)
+}
+
 // getCallerFunctionName returns the name of the calling function.
 func getCallerFunctionName() string {
 	pc, _, _, _ := runtime.Caller(2)